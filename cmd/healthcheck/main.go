@@ -0,0 +1,151 @@
+// Command healthcheck retrieves a Health document and exits with a code
+// reflecting its Status, making it suitable for a Docker HEALTHCHECK or a
+// script in an image without curl.
+//
+// Usage:
+//
+//	healthcheck [flags] url
+//
+// The exit code is 0 for pass, the value of -warn-exit for warn and 1 for
+// any other status or if the Health document couldn't be retrieved or
+// decoded.  A response without a valid status (e.g. a proxy's JSON error
+// page) is never treated as healthy, whatever its HTTP status code.
+// Invalid usage exits with 2.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PennState/go-healthcheck/pkg/health"
+)
+
+const (
+	exitPass  = 0
+	exitFail  = 1
+	exitUsage = 2
+)
+
+// headers accumulates repeated -header flags.
+type headers http.Header
+
+func (h headers) String() string {
+	var pairs []string
+	for name, values := range h {
+		for _, value := range values {
+			pairs = append(pairs, name+": "+value)
+		}
+	}
+	return strings.Join(pairs, ", ")
+}
+
+func (h headers) Set(s string) error {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+		return fmt.Errorf("Header must be formatted as \"Name: value\": %v", s)
+	}
+	http.Header(h).Add(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+	return nil
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command and returns its exit code.
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("healthcheck", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: healthcheck [flags] url")
+		flags.PrintDefaults()
+	}
+	header := headers{}
+	flags.Var(header, "header", "add a request header formatted as \"Name: value\" (repeatable)")
+	timeout := flags.Duration("timeout", 5*time.Second, "maximum time to wait for the health document")
+	insecure := flags.Bool("insecure", false, "skip verification of the server's TLS certificate")
+	warnExit := flags.Int("warn-exit", exitPass, "exit code used when the status is warn")
+	summary := flags.Bool("summary", false, "print a one-line summary of the health document")
+	failing := flags.Bool("failing", false, "print the key and status of each check that didn't pass")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+	url := flags.Arg(0)
+
+	client := health.Client{
+		HTTPClient: &http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: *insecure},
+			},
+		},
+		Header: http.Header(header),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	h, err := client.GetContext(ctx, url)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFail
+	}
+
+	if *summary {
+		fmt.Fprintln(stdout, summarize(url, h))
+	}
+	if *failing {
+		for _, line := range failingChecks(h) {
+			fmt.Fprintln(stdout, line)
+		}
+	}
+
+	switch h.Status {
+	case health.Pass:
+		return exitPass
+	case health.Warn:
+		return *warnExit
+	default:
+		return exitFail
+	}
+}
+
+// summarize describes the Health document in a single line.
+func summarize(url string, h health.Health) string {
+	counts := map[health.Status]int{}
+	total := 0
+	for _, details := range h.Checks {
+		for _, detail := range details {
+			counts[detail.Status]++
+			total++
+		}
+	}
+	return fmt.Sprintf("%v %v: %d checks, %d warn, %d undetermined, %d fail",
+		h.Status, url, total, counts[health.Warn], counts[health.Undetermined], counts[health.Fail])
+}
+
+// failingChecks lists the key and status of each ComponentDetail that
+// didn't pass, ordered by key.
+func failingChecks(h health.Health) []string {
+	var lines []string
+	for key, details := range h.Checks {
+		for _, detail := range details {
+			if detail.Status != health.Pass {
+				lines = append(lines, fmt.Sprintf("%v %v", key, detail.Status))
+			}
+		}
+	}
+	sort.Strings(lines)
+	return lines
+}
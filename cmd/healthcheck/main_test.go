@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const warnDocument = `{
+  "status": "warn",
+  "checks": {
+    "cassandra:responseTime": [{"status": "pass"}],
+    "cpu:utilization": [{"status": "warn"}, {"status": "fail"}]
+  }
+}`
+
+func serve(code int, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(code)
+		w.Write([]byte(body))
+	}))
+}
+
+func TestExitCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		code int
+		body string
+		args []string
+		exit int
+	}{
+		{"pass", http.StatusOK, `{"status": "pass"}`, nil, 0},
+		{"fail", http.StatusServiceUnavailable, `{"status": "fail"}`, nil, 1},
+		{"undetermined", http.StatusServiceUnavailable, `{"status": "unknown"}`, nil, 1},
+		{"warn defaults to pass", http.StatusOK, `{"status": "warn"}`, nil, 0},
+		{"warn exit", http.StatusOK, `{"status": "warn"}`, []string{"-warn-exit", "3"}, 3},
+		{"malformed", http.StatusOK, `<html>`, nil, 1},
+		{"missing status", http.StatusOK, `{}`, nil, 1},
+		{"proxy error", http.StatusServiceUnavailable, `{"message":"no healthy upstream"}`, nil, 1},
+		{"bad gateway", http.StatusBadGateway, `<html>Bad Gateway</html>`, nil, 1},
+		{"unknown status", http.StatusOK, `{"status": "sideways"}`, nil, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := serve(test.code, test.body)
+			defer server.Close()
+
+			var stdout, stderr bytes.Buffer
+			exit := run(append(test.args, server.URL), &stdout, &stderr)
+			assert.Equal(t, test.exit, exit, stderr.String())
+		})
+	}
+}
+
+func TestUnreachable(t *testing.T) {
+	server := serve(http.StatusOK, `{"status": "pass"}`)
+	server.Close()
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 1, run([]string{server.URL}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "Unable to retrieve health")
+}
+
+func TestUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 2, run(nil, &stdout, &stderr))
+	assert.Equal(t, 2, run([]string{"-header", "invalid", "http://localhost"}, &stdout, &stderr))
+	assert.Equal(t, 2, run([]string{"-unknown", "http://localhost"}, &stdout, &stderr))
+}
+
+func TestSummaryAndFailing(t *testing.T) {
+	server := serve(http.StatusOK, warnDocument)
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	exit := run([]string{"-summary", "-failing", server.URL}, &stdout, &stderr)
+	assert.Equal(t, 0, exit)
+	assert.Equal(t, "Warn "+server.URL+": 3 checks, 1 warn, 0 undetermined, 1 fail\n"+
+		"cpu:utilization Fail\n"+
+		"cpu:utilization Warn\n", stdout.String())
+}
+
+func TestHeaders(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+		w.Write([]byte(`{"status": "pass"}`))
+	}))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-header", "Authorization: Bearer token", "-header", "X-Probe:docker", server.URL}
+	assert.Equal(t, 0, run(args, &stdout, &stderr))
+	assert.Equal(t, "Bearer token", got.Get("Authorization"))
+	assert.Equal(t, "docker", got.Get("X-Probe"))
+}
+
+func TestInsecure(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "pass"}`))
+	}))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 1, run([]string{server.URL}, &stdout, &stderr))
+	assert.Equal(t, 0, run([]string{"-insecure", server.URL}, &stdout, &stderr))
+}
+
+func TestProxyErrorPageFails(t *testing.T) {
+	server := serve(http.StatusServiceUnavailable, `{"message":"no healthy upstream"}`)
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	exit := run([]string{server.URL}, &stdout, &stderr)
+
+	assert.Equal(t, exitFail, exit)
+	assert.Contains(t, stderr.String(), "missing a status")
+	assert.Empty(t, stdout.String())
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/PennState/go-healthcheck/pkg/health"
+)
+
+// diff lists the differences in status, observed values and output
+// between two Health documents.  Checks that were added are prefixed with
+// "+", those that were removed with "-" and those that changed with "~".
+func diff(from health.Health, to health.Health, colors palette) []string {
+	var lines []string
+	if from.Status != to.Status {
+		lines = append(lines, fmt.Sprintf("~ status: %v -> %v", colors.status(from.Status), colors.status(to.Status)))
+	}
+
+	keys := map[health.Key]bool{}
+	for key := range from.Checks {
+		keys[key] = true
+	}
+	for key := range to.Checks {
+		keys[key] = true
+	}
+	sorted := make([]health.Key, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].String() < sorted[j].String() })
+
+	for _, key := range sorted {
+		before, after := from.Checks[key], to.Checks[key]
+		for i := 0; i < len(before) || i < len(after); i++ {
+			path := fmt.Sprintf("checks[%q][%d]", key.String(), i)
+			switch {
+			case i >= len(before):
+				lines = append(lines, fmt.Sprintf("+ %s: %v", path, colors.status(after[i].Status)))
+			case i >= len(after):
+				lines = append(lines, fmt.Sprintf("- %s: %v", path, colors.status(before[i].Status)))
+			default:
+				lines = append(lines, diffDetail(path, before[i], after[i], colors)...)
+			}
+		}
+	}
+	return lines
+}
+
+func diffDetail(path string, from health.ComponentDetail, to health.ComponentDetail, colors palette) []string {
+	var lines []string
+	if from.Status != to.Status {
+		lines = append(lines, fmt.Sprintf("~ %s.status: %v -> %v", path, colors.status(from.Status), colors.status(to.Status)))
+	}
+	if !reflect.DeepEqual(from.ObservedValue, to.ObservedValue) || from.ObservedUnit != to.ObservedUnit {
+		lines = append(lines, fmt.Sprintf("~ %s.observedValue: %v -> %v", path, observed(from), observed(to)))
+	}
+	if from.Output != to.Output {
+		lines = append(lines, fmt.Sprintf("~ %s.output: %q -> %q", path, from.Output, to.Output))
+	}
+	return lines
+}
+
+func observed(detail health.ComponentDetail) string {
+	if detail.ObservedValue == nil {
+		return "none"
+	}
+	if detail.ObservedUnit == "" {
+		return fmt.Sprintf("%v", detail.ObservedValue)
+	}
+	return fmt.Sprintf("%v %v", detail.ObservedValue, detail.ObservedUnit)
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/PennState/go-healthcheck/pkg/health"
+)
+
+// lint decodes the Health document, reporting every way in which it
+// doesn't conform to the RFC and whether it could be decoded regardless.
+// Since decoding into a health.Health stops at the first error, the
+// document is first inspected generically so that all of its malformed
+// fields are reported.
+func lint(data []byte) (health.Health, health.Violations, bool) {
+	var h health.Health
+
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return h, health.Violations{{Message: fmt.Sprintf("Invalid JSON: %v", err)}}, false
+	}
+	obj, ok := doc.(map[string]interface{})
+	if !ok {
+		return h, health.Violations{{Message: fmt.Sprintf("Document must be an object, not %v", typeName(doc))}}, false
+	}
+
+	v := lintHealth(obj)
+	aliases := lintStatusAliases(obj)
+	if len(v) != 0 {
+		return h, append(v, aliases...), false
+	}
+	if err := json.Unmarshal(data, &h); err != nil {
+		return h, health.Violations{{Message: fmt.Sprintf("Unable to decode health: %v", err)}}, false
+	}
+	return h, append(h.Validate(), aliases...), true
+}
+
+func lintHealth(obj map[string]interface{}) health.Violations {
+	var v health.Violations
+	if _, ok := obj["status"]; !ok {
+		v = append(v, health.Violation{Path: "status", Message: "Status is required"})
+	}
+	for _, name := range sortedNames(obj) {
+		value := obj[name]
+		switch name {
+		case "status":
+			v = append(v, lintStatus(name, value)...)
+		case "version", "releaseId", "output", "serviceId", "description":
+			v = append(v, lintString(name, value)...)
+		case "notes":
+			v = append(v, lintStrings(name, value)...)
+		case "links":
+			v = append(v, lintLinks(name, value)...)
+		case "checks":
+			v = append(v, lintChecks(name, value)...)
+		}
+	}
+	return v
+}
+
+func lintChecks(path string, value interface{}) health.Violations {
+	checks, ok := value.(map[string]interface{})
+	if !ok {
+		return wrongType(path, "an object", value)
+	}
+
+	var v health.Violations
+	for _, name := range sortedNames(checks) {
+		keyPath := fmt.Sprintf("%s[%q]", path, name)
+		var key health.Key
+		if err := key.UnmarshalText([]byte(name)); err != nil {
+			v = append(v, health.Violation{Path: keyPath, Message: fmt.Sprintf("Malformed key: %v", err)})
+		} else {
+			for _, violation := range key.Validate() {
+				violation.Path = keyPath + "." + violation.Path
+				v = append(v, violation)
+			}
+		}
+
+		details, ok := checks[name].([]interface{})
+		if !ok {
+			v = append(v, wrongType(keyPath, "an array", checks[name])...)
+			continue
+		}
+		for i, detail := range details {
+			v = append(v, lintDetail(fmt.Sprintf("%s[%d]", keyPath, i), detail)...)
+		}
+	}
+	return v
+}
+
+func lintDetail(path string, value interface{}) health.Violations {
+	detail, ok := value.(map[string]interface{})
+	if !ok {
+		return wrongType(path, "an object", value)
+	}
+
+	var v health.Violations
+	for _, name := range sortedNames(detail) {
+		fieldPath := path + "." + name
+		value := detail[name]
+		switch name {
+		case "status":
+			v = append(v, lintStatus(fieldPath, value)...)
+		case "componentId", "componentType", "observedUnit", "output":
+			v = append(v, lintString(fieldPath, value)...)
+		case "affectedEndpoints":
+			v = append(v, lintStrings(fieldPath, value)...)
+		case "links":
+			v = append(v, lintLinks(fieldPath, value)...)
+		case "time":
+			s, ok := value.(string)
+			if !ok {
+				v = append(v, wrongType(fieldPath, "a string", value)...)
+			} else if _, err := time.Parse(time.RFC3339, s); err != nil {
+				v = append(v, health.Violation{Path: fieldPath, Message: fmt.Sprintf("Time must be formatted as RFC 3339: %q", s)})
+			}
+		}
+	}
+	return v
+}
+
+func lintStatus(path string, value interface{}) health.Violations {
+	s, ok := value.(string)
+	if !ok {
+		return wrongType(path, "a string", value)
+	}
+	if _, err := health.ParseStatus(s); err != nil {
+		return health.Violations{{Path: path, Message: fmt.Sprintf("%q is not a known status", s)}}
+	}
+	return nil
+}
+
+// lintStatusAliases reports the statuses that health.ParseStatus accepts
+// but the RFC doesn't (e.g. "up" or "ok").  They don't prevent the
+// document from being decoded, but stricter consumers will reject them.
+func lintStatusAliases(obj map[string]interface{}) health.Violations {
+	var v health.Violations
+	checks, _ := obj["checks"].(map[string]interface{})
+	for _, name := range sortedNames(checks) {
+		details, _ := checks[name].([]interface{})
+		for i, detail := range details {
+			if detail, ok := detail.(map[string]interface{}); ok {
+				v = append(v, lintStatusAlias(fmt.Sprintf("checks[%q][%d].status", name, i), detail["status"])...)
+			}
+		}
+	}
+	return append(v, lintStatusAlias("status", obj["status"])...)
+}
+
+func lintStatusAlias(path string, value interface{}) health.Violations {
+	s, ok := value.(string)
+	if !ok {
+		return nil
+	}
+	status, err := health.ParseStatus(s)
+	if err != nil {
+		return nil
+	}
+	canonical, err := status.MarshalText()
+	if err != nil || s == string(canonical) {
+		return nil
+	}
+	return health.Violations{{Path: path, Message: fmt.Sprintf("%q is not an RFC status, use %q", s, canonical)}}
+}
+
+func lintString(path string, value interface{}) health.Violations {
+	if _, ok := value.(string); !ok {
+		return wrongType(path, "a string", value)
+	}
+	return nil
+}
+
+func lintStrings(path string, value interface{}) health.Violations {
+	values, ok := value.([]interface{})
+	if !ok {
+		return wrongType(path, "an array of strings", value)
+	}
+	var v health.Violations
+	for i, value := range values {
+		v = append(v, lintString(fmt.Sprintf("%s[%d]", path, i), value)...)
+	}
+	return v
+}
+
+func lintLinks(path string, value interface{}) health.Violations {
+	links, ok := value.(map[string]interface{})
+	if !ok {
+		return wrongType(path, "an object", value)
+	}
+	var v health.Violations
+	for _, name := range sortedNames(links) {
+		v = append(v, lintString(fmt.Sprintf("%s[%q]", path, name), links[name])...)
+	}
+	return v
+}
+
+func wrongType(path string, want string, value interface{}) health.Violations {
+	return health.Violations{{Path: path, Message: fmt.Sprintf("Must be %s, not %v", want, typeName(value))}}
+}
+
+// typeName returns the JSON type of a generically decoded value.
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case float64:
+		return "a number"
+	case string:
+		return "a string"
+	case []interface{}:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
+
+func sortedNames(obj map[string]interface{}) []string {
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/PennState/go-healthcheck/pkg/health"
+	"github.com/stretchr/testify/assert"
+)
+
+func violationStrings(v health.Violations) []string {
+	var s []string
+	for _, violation := range v {
+		s = append(s, violation.String())
+	}
+	return s
+}
+
+func TestLintValidDocument(t *testing.T) {
+	h, violations, decoded := lint([]byte(`{"status": "pass", "checks": {"cpu:utilization": [{"status": "warn"}]}}`))
+	assert.True(t, decoded)
+	assert.Empty(t, violations)
+	assert.Equal(t, health.Pass, h.Status)
+}
+
+func TestLintStatusAliases(t *testing.T) {
+	h, violations, decoded := lint([]byte(`{
+		"status": "up",
+		"checks": {
+			"cpu:utilization": [{"status": "ok"}, {"status": "Pass"}],
+			"db": [{"status": "unknown"}, {"status": "DOWN"}, {"status": "fail"}]
+		}
+	}`))
+	assert.True(t, decoded)
+	assert.Equal(t, health.Pass, h.Status)
+	assert.Equal(t, []string{
+		`checks["cpu:utilization"][0].status: "ok" is not an RFC status, use "pass"`,
+		`checks["cpu:utilization"][1].status: "Pass" is not an RFC status, use "pass"`,
+		`checks["db"][0].status: "unknown" is not an RFC status, use "fail"`,
+		`checks["db"][1].status: "DOWN" is not an RFC status, use "fail"`,
+		`status: "up" is not an RFC status, use "pass"`,
+	}, violationStrings(violations))
+}
+
+func TestLintReportsEveryViolation(t *testing.T) {
+	_, violations, decoded := lint([]byte(`{
+		"status": "great",
+		"version": 1,
+		"notes": ["ok", false],
+		"checks": {
+			":uptime": [{"status": "pass"}],
+			"cpu:utilization": [{"status": 3, "time": "yesterday", "affectedEndpoints": "/"}],
+			"memory": {"status": "pass"}
+		}
+	}`))
+	assert.False(t, decoded)
+	assert.Equal(t, []string{
+		`checks[":uptime"].componentName: ComponentName is required`,
+		`checks["cpu:utilization"][0].affectedEndpoints: Must be an array of strings, not a string`,
+		`checks["cpu:utilization"][0].status: Must be a string, not a number`,
+		`checks["cpu:utilization"][0].time: Time must be formatted as RFC 3339: "yesterday"`,
+		`checks["memory"]: Must be an array, not an object`,
+		`notes[1]: Must be a string, not a boolean`,
+		`status: "great" is not a known status`,
+		`version: Must be a string, not a number`,
+	}, violationStrings(violations))
+}
+
+func TestLintMissingStatus(t *testing.T) {
+	_, violations, decoded := lint([]byte(`{"version": "1"}`))
+	assert.False(t, decoded)
+	assert.Equal(t, []string{"status: Status is required"}, violationStrings(violations))
+}
+
+func TestLintInvalidJSON(t *testing.T) {
+	_, violations, decoded := lint([]byte(`{"status": `))
+	assert.False(t, decoded)
+	assert.Len(t, violations, 1)
+
+	_, violations, decoded = lint([]byte(`["pass"]`))
+	assert.False(t, decoded)
+	assert.Equal(t, []string{"Document must be an object, not an array"}, violationStrings(violations))
+}
@@ -0,0 +1,189 @@
+// Command healthlint validates Health documents against the RFC and
+// pretty-prints them as a tree of components and measurements.
+//
+// Usage:
+//
+//	healthlint [flags] [file | url | -]
+//	healthlint -diff [flags] old new
+//
+// Documents are read from a file, an http(s) URL or, if no source (or
+// "-") is provided, stdin.  The exit code is 0 if the document is valid,
+// 1 if it has violations and 2 if it couldn't be read.  In diff mode, the
+// exit code is 0 if the documents are equivalent, 1 if they differ and 2
+// if either couldn't be read or decoded.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/PennState/go-healthcheck/pkg/health"
+)
+
+const (
+	exitOK      = 0
+	exitProblem = 1
+	exitError   = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command and returns its exit code.
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("healthlint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: healthlint [flags] [file | url | -]")
+		fmt.Fprintln(stderr, "       healthlint -diff [flags] old new")
+		flags.PrintDefaults()
+	}
+	diffMode := flags.Bool("diff", false, "compare two health documents")
+	color := flags.String("color", "auto", "colorize the output: auto, always or never")
+	timeout := flags.Duration("timeout", 10*time.Second, "maximum time to wait for a health document retrieved from a URL")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	colors, err := colorize(*color, stdout)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	r := reader{stdin: stdin, timeout: *timeout}
+
+	if *diffMode {
+		if flags.NArg() != 2 {
+			flags.Usage()
+			return exitError
+		}
+		return runDiff(r, flags.Arg(0), flags.Arg(1), stdout, stderr, colors)
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return exitError
+	}
+	return runLint(r, flags.Arg(0), stdout, stderr, colors)
+}
+
+func runLint(r reader, source string, stdout io.Writer, stderr io.Writer, colors palette) int {
+	data, err := r.read(source)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	h, violations, decoded := lint(data)
+	if decoded {
+		printTree(stdout, h, colors)
+	}
+	if len(violations) == 0 {
+		return exitOK
+	}
+	fmt.Fprintf(stdout, "%d RFC violation(s):\n", len(violations))
+	for _, v := range violations {
+		fmt.Fprintln(stdout, "  "+v.String())
+	}
+	return exitProblem
+}
+
+func runDiff(r reader, from string, to string, stdout io.Writer, stderr io.Writer, colors palette) int {
+	var docs [2]health.Health
+	for i, source := range []string{from, to} {
+		data, err := r.read(source)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+		h, violations, decoded := lint(data)
+		if !decoded {
+			fmt.Fprintf(stderr, "Unable to decode %v: %v\n", displayName(source), violations)
+			return exitError
+		}
+		docs[i] = h
+	}
+
+	lines := diff(docs[0], docs[1], colors)
+	for _, line := range lines {
+		fmt.Fprintln(stdout, line)
+	}
+	if len(lines) != 0 {
+		return exitProblem
+	}
+	return exitOK
+}
+
+// reader reads Health documents from files, URLs and stdin.
+type reader struct {
+	stdin   io.Reader
+	timeout time.Duration
+}
+
+func (r reader) read(source string) ([]byte, error) {
+	switch {
+	case source == "" || source == "-":
+		return ioutil.ReadAll(r.stdin)
+	case strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://"):
+		return r.fetch(source)
+	default:
+		return ioutil.ReadFile(source)
+	}
+}
+
+// fetch retrieves the raw document, rather than using health.Client, so
+// that it can be linted before it's decoded.
+func (r reader) fetch(url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", health.ContentType+", application/json;q=0.9")
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return ioutil.ReadAll(resp.Body)
+}
+
+func displayName(source string) string {
+	if source == "" || source == "-" {
+		return "stdin"
+	}
+	return source
+}
+
+// colorize decides whether the output is colored.  In auto mode, the
+// output is only colored when written to a terminal and the NO_COLOR
+// environment variable isn't set.
+func colorize(mode string, stdout io.Writer) (palette, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		if _, ok := os.LookupEnv("NO_COLOR"); ok {
+			return false, nil
+		}
+		f, ok := stdout.(*os.File)
+		if !ok {
+			return false, nil
+		}
+		info, err := f.Stat()
+		return palette(err == nil && info.Mode()&os.ModeCharDevice != 0), nil
+	default:
+		return false, fmt.Errorf("Color must be auto, always or never: %v", mode)
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const before = `{
+  "status": "pass",
+  "serviceId": "orders",
+  "version": "1",
+  "checks": {
+    "cassandra:responseTime": [{"componentId": "node-1", "status": "pass", "observedValue": 250, "observedUnit": "ms"}],
+    "cpu:utilization": [{"status": "pass", "observedValue": 40, "observedUnit": "percent"}],
+    "uptime": [{"status": "pass"}]
+  }
+}`
+
+const after = `{
+  "status": "warn",
+  "checks": {
+    "cassandra:responseTime": [{"componentId": "node-1", "status": "warn", "observedValue": 900, "observedUnit": "ms", "output": "slow"}],
+    "cpu:utilization": [{"status": "pass", "observedValue": 40, "observedUnit": "percent"}],
+    "disk:free": [{"status": "pass"}]
+  }
+}`
+
+func writeFile(t *testing.T, dir string, name string, contents string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(contents), 0600))
+	return path
+}
+
+func TestTree(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	exit := run([]string{"-color", "never"}, strings.NewReader(before), &stdout, &stderr)
+	assert.Equal(t, 0, exit, stderr.String())
+	assert.Equal(t, strings.Join([]string{
+		"pass orders 1",
+		"├── cassandra",
+		"│   └── responseTime (node-1) pass 250 ms",
+		"├── cpu",
+		"│   └── utilization pass 40 percent",
+		"└── uptime",
+		"    └── - pass",
+		"",
+	}, "\n"), stdout.String())
+}
+
+func TestTreeColors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	run([]string{"-color", "always", "-"}, strings.NewReader(`{"status": "fail"}`), &stdout, &stderr)
+	assert.Equal(t, "\x1b[31mfail\x1b[0m\n", stdout.String())
+}
+
+func TestViolations(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	exit := run(nil, strings.NewReader(`{"status": "sideways"}`), &stdout, &stderr)
+	assert.Equal(t, 1, exit)
+	assert.Equal(t, "1 RFC violation(s):\n  status: \"sideways\" is not a known status\n", stdout.String())
+}
+
+func TestReadFileAndURL(t *testing.T) {
+	dir, err := ioutil.TempDir("", "healthlint")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := writeFile(t, dir, "health.json", before)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"status": "fail"}`))
+	}))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 0, run([]string{"-color", "never", path}, nil, &stdout, &stderr))
+	assert.True(t, strings.HasPrefix(stdout.String(), "pass orders 1\n"))
+
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"-color", "never", server.URL}, nil, &stdout, &stderr))
+	assert.Equal(t, "fail\n", stdout.String())
+
+	assert.Equal(t, 2, run([]string{filepath.Join(dir, "missing.json")}, nil, &stdout, &stderr))
+}
+
+func TestDiff(t *testing.T) {
+	dir, err := ioutil.TempDir("", "healthlint")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	from := writeFile(t, dir, "before.json", before)
+	to := writeFile(t, dir, "after.json", after)
+
+	var stdout, stderr bytes.Buffer
+	exit := run([]string{"-diff", "-color", "never", from, to}, nil, &stdout, &stderr)
+	assert.Equal(t, 1, exit, stderr.String())
+	assert.Equal(t, strings.Join([]string{
+		"~ status: pass -> warn",
+		`~ checks["cassandra:responseTime"][0].status: pass -> warn`,
+		`~ checks["cassandra:responseTime"][0].observedValue: 250 ms -> 900 ms`,
+		`~ checks["cassandra:responseTime"][0].output: "" -> "slow"`,
+		`+ checks["disk:free"][0]: pass`,
+		`- checks["uptime"][0]: pass`,
+		"",
+	}, "\n"), stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"-diff", from, from}, nil, &stdout, &stderr))
+	assert.Empty(t, stdout.String())
+
+	invalid := writeFile(t, dir, "invalid.json", `{"status": 1}`)
+	assert.Equal(t, 2, run([]string{"-diff", from, invalid}, nil, &stdout, &stderr))
+}
+
+func TestDiffUndetermined(t *testing.T) {
+	dir, err := ioutil.TempDir("", "healthlint")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	from := writeFile(t, dir, "before.json", `{"status": "unknown"}`)
+	to := writeFile(t, dir, "after.json", `{"status": "fail"}`)
+
+	var stdout, stderr bytes.Buffer
+	exit := run([]string{"-diff", "-color", "never", from, to}, nil, &stdout, &stderr)
+	assert.Equal(t, 1, exit, stderr.String())
+	assert.Equal(t, "~ status: undetermined -> fail\n", stdout.String())
+
+	stdout.Reset()
+	run([]string{"-color", "never", from}, nil, &stdout, &stderr)
+	assert.True(t, strings.HasPrefix(stdout.String(), "undetermined\n"), stdout.String())
+}
+
+func TestUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 2, run([]string{"-diff", "one"}, nil, &stdout, &stderr))
+	assert.Equal(t, 2, run([]string{"one", "two"}, nil, &stdout, &stderr))
+	assert.Equal(t, 2, run([]string{"-color", "sometimes"}, nil, &stdout, &stderr))
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/PennState/go-healthcheck/pkg/health"
+)
+
+// palette colors Statuses using ANSI escape codes when enabled.
+type palette bool
+
+// status names the Status, distinguishing Undetermined (decoded from
+// "unknown") from Fail even though both are encoded as "fail".
+func (p palette) status(s health.Status) string {
+	text := strings.ToLower(s.String())
+	if !p {
+		return text
+	}
+	code := "35"
+	switch s {
+	case health.Pass:
+		code = "32"
+	case health.Warn:
+		code = "33"
+	case health.Fail:
+		code = "31"
+	}
+	return "\x1b[" + code + "m" + text + "\x1b[0m"
+}
+
+// printTree writes the Health document as a tree of components and their
+// measurements.
+func printTree(w io.Writer, h health.Health, colors palette) {
+	header := []string{colors.status(h.Status)}
+	for _, field := range []string{h.ServiceId, h.Description, h.Version, h.ReleaseId} {
+		if field != "" {
+			header = append(header, field)
+		}
+	}
+	fmt.Fprintln(w, strings.Join(header, " "))
+	for _, note := range h.Notes {
+		fmt.Fprintln(w, "  note: "+note)
+	}
+	if h.Output != "" {
+		fmt.Fprintln(w, "  output: "+h.Output)
+	}
+
+	components := map[string][]health.Key{}
+	for key := range h.Checks {
+		components[key.ComponentName] = append(components[key.ComponentName], key)
+	}
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		branch, indent := "├── ", "│   "
+		if i == len(names)-1 {
+			branch, indent = "└── ", "    "
+		}
+		fmt.Fprintln(w, branch+name)
+
+		keys := components[name]
+		sort.Slice(keys, func(i, j int) bool { return keys[i].MeasurementName < keys[j].MeasurementName })
+		var lines []string
+		for _, key := range keys {
+			for _, detail := range h.Checks[key] {
+				lines = append(lines, describe(key, detail, colors))
+			}
+		}
+		for j, line := range lines {
+			if j == len(lines)-1 {
+				fmt.Fprintln(w, indent+"└── "+line)
+			} else {
+				fmt.Fprintln(w, indent+"├── "+line)
+			}
+		}
+	}
+}
+
+// describe summarizes a ComponentDetail on a single line.
+func describe(key health.Key, detail health.ComponentDetail, colors palette) string {
+	parts := []string{key.MeasurementName}
+	if key.MeasurementName == "" {
+		parts[0] = "-"
+	}
+	if detail.ComponentId != "" {
+		parts[0] += " (" + detail.ComponentId + ")"
+	}
+	parts = append(parts, colors.status(detail.Status))
+	if detail.ObservedValue != nil {
+		parts = append(parts, strings.TrimSpace(fmt.Sprintf("%v %v", detail.ObservedValue, detail.ObservedUnit)))
+	}
+	if detail.Output != "" {
+		parts = append(parts, "- "+detail.Output)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/PennState/go-healthcheck/pkg/health"
+)
+
+const (
+	// componentSeparator joins the Name of a HealthCheck to the
+	// ComponentName of each embedded check.
+	componentSeparator = "/"
+
+	downstreamMeasurementName = "status"
+)
+
+// HealthCheck retrieves the Health document of a downstream service and
+// embeds its Checks into the local output.  Each embedded Key's
+// ComponentName is prefixed with the HealthCheck's Name (e.g. the
+// downstream's "cassandra:responseTime" becomes
+// "billing/cassandra:responseTime") and an additional "<Name>:status"
+// check reports the downstream's overall Status.
+//
+// Every Status is mapped through the HealthCheck's Criticality using
+// health.WithCriticality, so checks whose Status is changed by the mapping
+// explain the change in their Output and retain the downstream's Status in
+// the "rawStatus" additional property.
+type HealthCheck struct {
+	Client      health.Client
+	URL         string
+	Name        string
+	Criticality health.Criticality
+}
+
+// Check executes CheckContext with a background context.
+func (h HealthCheck) Check() ([]health.ComponentDetail, health.Status) {
+	return h.CheckContext(context.Background())
+}
+
+// CheckContext retrieves the downstream's Health document, abandoning the
+// request when ctx is done.  Unless the Client has its own timeout, or ctx
+// already has a deadline, the request is bounded by a default timeout.
+func (h HealthCheck) CheckContext(ctx context.Context) ([]health.ComponentDetail, health.Status) {
+	var clientTimeout time.Duration
+	if h.Client.HTTPClient != nil {
+		clientTimeout = h.Client.HTTPClient.Timeout
+	}
+	ctx, cancel := withDefaultTimeout(ctx, clientTimeout)
+	defer cancel()
+
+	return health.WithCriticality(downstreamCheck(h), h.Criticality).CheckContext(ctx)
+}
+
+// downstreamCheck reports the downstream's Statuses before they're mapped
+// through the HealthCheck's Criticality.
+type downstreamCheck HealthCheck
+
+func (d downstreamCheck) Check() ([]health.ComponentDetail, health.Status) {
+	return d.CheckContext(context.Background())
+}
+
+func (d downstreamCheck) CheckContext(ctx context.Context) ([]health.ComponentDetail, health.Status) {
+	name := d.Name
+	if name == "" {
+		name = d.URL
+	}
+
+	statusCheck := health.ComponentDetail{
+		Key: health.Key{
+			ComponentName:   name,
+			MeasurementName: downstreamMeasurementName,
+		},
+		ComponentType: "component",
+		Links:         map[string]string{"self": d.URL},
+		Time:          time.Now().UTC(),
+	}
+
+	downstream, err := d.Client.GetContext(ctx, d.URL)
+	if err != nil {
+		statusCheck.Status = health.Fail
+		statusCheck.Output = err.Error()
+		return []health.ComponentDetail{statusCheck}, statusCheck.Status
+	}
+
+	statusCheck.Status = downstream.Status
+	statusCheck.ObservedValue = downstream.Status
+	statusCheck.Output = downstream.Output
+	checks := []health.ComponentDetail{statusCheck}
+
+	keys := make([]health.Key, 0, len(downstream.Checks))
+	for key := range downstream.Checks {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	for _, key := range keys {
+		embedded := key
+		embedded.ComponentName = name + componentSeparator + key.ComponentName
+		for _, detail := range downstream.Checks[key] {
+			detail.Key = embedded
+			checks = append(checks, detail)
+		}
+	}
+
+	return checks, statusCheck.Status
+}
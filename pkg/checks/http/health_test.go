@@ -0,0 +1,182 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/PennState/go-healthcheck/pkg/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type downstreamChecker struct {
+	details []health.ComponentDetail
+	status  health.Status
+}
+
+func (d downstreamChecker) Check() ([]health.ComponentDetail, health.Status) {
+	return d.details, d.status
+}
+
+func downstreamServer(status health.Status) *httptest.Server {
+	return httptest.NewServer(health.NewHandler(health.Service{}, downstreamChecker{
+		details: []health.ComponentDetail{{
+			Key:           health.Key{ComponentName: "cassandra", MeasurementName: "responseTime"},
+			ObservedValue: float64(250),
+			ObservedUnit:  "ms",
+			Status:        status,
+		}},
+		status: status,
+	}))
+}
+
+func findDetail(details []health.ComponentDetail, key health.Key) *health.ComponentDetail {
+	for i := range details {
+		if details[i].Key == key {
+			return &details[i]
+		}
+	}
+	return nil
+}
+
+func TestHealthCheckEmbedsDownstreamChecks(t *testing.T) {
+	assert := assert.New(t)
+	server := downstreamServer(health.Pass)
+	defer server.Close()
+
+	check := HealthCheck{URL: server.URL, Name: "billing"}
+	details, status := check.Check()
+
+	assert.Equal(health.Pass, status)
+	require.Len(t, details, 2)
+
+	statusCheck := findDetail(details, health.Key{ComponentName: "billing", MeasurementName: downstreamMeasurementName})
+	require.NotNil(t, statusCheck)
+	assert.Equal(health.Pass, statusCheck.Status)
+	assert.Equal(server.URL, statusCheck.Links["self"])
+
+	embedded := findDetail(details, health.Key{ComponentName: "billing/cassandra", MeasurementName: "responseTime"})
+	require.NotNil(t, embedded)
+	assert.Equal(float64(250), embedded.ObservedValue)
+	assert.Equal("ms", embedded.ObservedUnit)
+	assert.Equal(health.Pass, embedded.Status)
+}
+
+func TestHealthCheckCriticalFailure(t *testing.T) {
+	server := downstreamServer(health.Fail)
+	defer server.Close()
+
+	details, status := HealthCheck{URL: server.URL, Name: "billing"}.Check()
+
+	assert.Equal(t, health.Fail, status)
+	embedded := findDetail(details, health.Key{ComponentName: "billing/cassandra", MeasurementName: "responseTime"})
+	require.NotNil(t, embedded)
+	assert.Equal(t, health.Fail, embedded.Status)
+	assert.Nil(t, embedded.AdditionalProperties)
+}
+
+func TestHealthCheckNonCriticalFailure(t *testing.T) {
+	server := downstreamServer(health.Fail)
+	defer server.Close()
+
+	check := HealthCheck{URL: server.URL, Name: "billing", Criticality: health.NonCritical}
+	details, status := check.Check()
+
+	assert.Equal(t, health.Warn, status)
+	embedded := findDetail(details, health.Key{ComponentName: "billing/cassandra", MeasurementName: "responseTime"})
+	require.NotNil(t, embedded)
+	assert.Equal(t, health.Warn, embedded.Status)
+	assert.Equal(t, health.Fail, embedded.AdditionalProperties["rawStatus"])
+	assert.Contains(t, embedded.Output, "fail reported as warn")
+}
+
+func TestHealthCheckOrdersEmbeddedChecks(t *testing.T) {
+	var details []health.ComponentDetail
+	for _, name := range []string{"memory", "cassandra", "uptime", "cpu", "disk"} {
+		details = append(details, health.ComponentDetail{Key: health.Key{ComponentName: name}})
+	}
+	server := httptest.NewServer(health.NewHandler(health.Service{}, downstreamChecker{details: details}))
+	defer server.Close()
+
+	check := HealthCheck{URL: server.URL, Name: "billing"}
+	for i := 0; i < 5; i++ {
+		details, _ := check.Check()
+
+		var names []string
+		for _, detail := range details[1:] {
+			names = append(names, detail.Key.ComponentName)
+		}
+		assert.Equal(t, []string{"billing/cassandra", "billing/cpu", "billing/disk", "billing/memory", "billing/uptime"}, names)
+	}
+}
+
+func TestHealthCheckUnreachable(t *testing.T) {
+	check := HealthCheck{
+		Client: health.Client{HTTPClient: &errClient},
+		URL:    successURL,
+		Name:   "billing",
+	}
+
+	details, status := check.Check()
+
+	assert.Equal(t, health.Fail, status)
+	require.Len(t, details, 1)
+	assert.Equal(t, health.Key{ComponentName: "billing", MeasurementName: downstreamMeasurementName}, details[0].Key)
+	assert.Contains(t, details[0].Output, testErr.Error())
+}
+
+func TestHealthCheckServedLocally(t *testing.T) {
+	server := downstreamServer(health.Warn)
+	defer server.Close()
+
+	rec := httptest.NewRecorder()
+	health.NewHandler(health.Service{}, HealthCheck{URL: server.URL, Name: "billing"}).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"billing/cassandra:responseTime"`)
+}
+
+func TestKeysRoundTrip(t *testing.T) {
+	downstream := downstreamServer(health.Pass)
+	defer downstream.Close()
+
+	checkers := []health.Checker{
+		Check{MustPassURLs: []string{downstream.URL}},
+		HealthCheck{URL: downstream.URL},
+		HealthCheck{URL: downstream.URL, Name: "billing"},
+	}
+	for _, checker := range checkers {
+		details, _ := checker.Check()
+		server := httptest.NewServer(health.NewHandler(health.Service{}, checker))
+
+		h, err := health.Client{}.Get(server.URL)
+		server.Close()
+
+		require.NoError(t, err)
+		assert.Len(t, h.Checks, len(details))
+		for _, detail := range details {
+			assert.Contains(t, h.Checks, detail.Key, "%#v", detail.Key)
+		}
+	}
+}
+
+func TestHealthCheckDefaultTimeoutWithoutDeadline(t *testing.T) {
+	defer func(timeout time.Duration) { defaultTimeout = timeout }(defaultTimeout)
+	defaultTimeout = 10 * time.Millisecond
+
+	check := HealthCheck{
+		Client: health.Client{HTTPClient: &http.Client{Transport: blockingRoundTripper{}}},
+		URL:    longDelayURL,
+		Name:   "billing",
+	}
+
+	details, status := check.CheckContext(context.Background())
+
+	assert.Equal(t, health.Fail, status)
+	require.Len(t, details, 1)
+	assert.Contains(t, details[0].Output, context.DeadlineExceeded.Error())
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"context"
 	"net/http"
 	"time"
 
@@ -28,61 +29,79 @@ type urlResult struct {
 	status health.Status
 }
 
+// Check executes CheckContext with a background context.
 func (h Check) Check() ([]health.ComponentDetail, health.Status) {
-	var checks []health.ComponentDetail
-
-	mustPassChecks := h.MustPassURLs[:]
-	mustPassResults := make(chan urlResult)
-
-	mayFailChecks := h.MayFailURLs[:]
-	mayFailResults := make(chan urlResult)
+	return h.CheckContext(context.Background())
+}
 
-	client := h.HttpClient
+// CheckContext requests each URL concurrently, abandoning the requests
+// when ctx is done.  Unless the HttpClient has its own timeout, or ctx
+// already has a deadline, the requests are bounded by a default timeout.
+//
+// All of the MustPassURLs must pass for the Check to pass, while the
+// MayFailURLs raise the Status to at most Warn.  The details of each URL
+// are reported unchanged, so a failing MayFailURL is still reported as
+// Fail.
+func (h Check) CheckContext(ctx context.Context) ([]health.ComponentDetail, health.Status) {
+	ctx, cancel := withDefaultTimeout(ctx, h.HttpClient.Timeout)
+	defer cancel()
+
+	mayFail := make(chan urlResult, 1)
+	go func() {
+		checks, status := health.AllOf(health.Key{}, h.urlCheckers(h.MayFailURLs)...).CheckContext(ctx)
+		mayFail <- urlResult{checks: checks, status: status}
+	}()
+
+	checks, status := health.AllOf(health.Key{}, h.urlCheckers(h.MustPassURLs)...).CheckContext(ctx)
+	r := <-mayFail
+	return append(checks, r.checks...), status.Max(health.NonCritical.Apply(r.status))
+}
 
-	// Ensure the client has a timeout
-	if client.Timeout == 0 {
-		client = clientWithDefaultTimeout(client)
+func (h Check) urlCheckers(urls []string) []health.Checker {
+	checkers := make([]health.Checker, len(urls))
+	for i, url := range urls {
+		checkers[i] = h.urlChecker(url)
 	}
+	return checkers
+}
 
-	for i := range mustPassChecks {
-		hc := mustPassChecks[i]
-		go checkURL(client, hc, mustPassResults)
+func (h Check) urlChecker(url string) health.TimeoutChecker {
+	return health.TimeoutChecker{
+		Key:     health.Key{ComponentName: url, MeasurementName: statusMeasurementName},
+		Checker: urlCheck{client: h.HttpClient, url: url},
 	}
-	for i := range mayFailChecks {
-		hc := mayFailChecks[i]
-		go checkURL(client, hc, mayFailResults)
-	}
-
-	overallStatus := health.Pass
+}
 
-	for range mustPassChecks {
-		urlResult := <-mustPassResults
-		if urlResult.status > overallStatus {
-			overallStatus = urlResult.status
-		}
+// urlCheck requests a single URL.
+type urlCheck struct {
+	client http.Client
+	url    string
+}
 
-		checks = append(checks, urlResult.checks...)
-	}
+func (u urlCheck) Check() ([]health.ComponentDetail, health.Status) {
+	return u.CheckContext(context.Background())
+}
 
-	for range mayFailChecks {
-		urlResult := <-mayFailResults
-		if urlResult.status > overallStatus {
-			// MayFailChecks will at most raise the status to Warn if they fail
-			overallStatus = health.Warn
-		}
+func (u urlCheck) CheckContext(ctx context.Context) ([]health.ComponentDetail, health.Status) {
+	r := checkURL(ctx, u.client, u.url)
+	return r.checks, r.status
+}
 
-		checks = append(checks, urlResult.checks...)
+// withDefaultTimeout bounds ctx by the default timeout unless the client
+// has its own timeout or ctx already has a deadline.
+func withDefaultTimeout(ctx context.Context, clientTimeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || clientTimeout > 0 {
+		return ctx, func() {}
 	}
-
-	return checks, overallStatus
+	return context.WithTimeout(ctx, defaultTimeout)
 }
 
-func checkURL(client http.Client, url string, ch chan urlResult) {
+func checkURL(ctx context.Context, client http.Client, url string) urlResult {
 	links := map[string]string{"target": url}
 
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
-		ch <- urlResult{
+		return urlResult{
 			checks: []health.ComponentDetail{
 				health.ComponentDetail{
 					Key: health.Key{
@@ -97,15 +116,14 @@ func checkURL(client http.Client, url string, ch chan urlResult) {
 				}},
 			status: health.Fail,
 		}
-		return
 	}
 
 	startTime := time.Now().UTC()
-	resp, err := client.Do(req)
+	resp, err := client.Do(req.WithContext(ctx))
 	requestDuration := time.Now().UTC().Sub(startTime)
 
 	if err != nil {
-		ch <- urlResult{
+		return urlResult{
 			checks: []health.ComponentDetail{
 				health.ComponentDetail{
 					Key: health.Key{
@@ -120,7 +138,6 @@ func checkURL(client http.Client, url string, ch chan urlResult) {
 				}},
 			status: health.Fail,
 		}
-		return
 	}
 
 	defer resp.Body.Close()
@@ -159,15 +176,8 @@ func checkURL(client http.Client, url string, ch chan urlResult) {
 		Status:        health.Pass,
 	}
 
-	ch <- urlResult{
+	return urlResult{
 		checks: []health.ComponentDetail{statusCheck, responseTimeCheck},
 		status: status,
 	}
 }
-
-func clientWithDefaultTimeout(client http.Client) http.Client {
-	clone := new(http.Client)
-	*clone = client
-	clone.Timeout = defaultTimeout
-	return *clone
-}
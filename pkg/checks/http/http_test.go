@@ -2,6 +2,7 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -59,6 +60,13 @@ var (
 
 type responses map[string]*http.Response
 
+type blockingRoundTripper struct{}
+
+func (b blockingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
 type testRoundTripper struct {
 	response responses
 	err      error
@@ -353,18 +361,35 @@ func TestFailRollup(t *testing.T) {
 	}
 }
 
-func TestCloneHttpClient(t *testing.T) {
-	client := http.Client{
-		Transport: &testRoundTripper{
-			response: responseMap,
-		},
+func TestContextDeadline(t *testing.T) {
+	var check health.ContextChecker = Check{
+		HttpClient:   http.Client{Transport: blockingRoundTripper{}},
+		MustPassURLs: []string{longDelayURL},
 	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	checks, status := check.CheckContext(ctx)
+
+	assert.Equal(t, health.Fail, status)
+	assert.Equal(t, 1, len(checks))
+	assert.Equal(t, health.Key{ComponentName: longDelayURL, MeasurementName: statusMeasurementName}, checks[0].Key)
+	assert.Regexp(t, "timed out|"+context.DeadlineExceeded.Error(), checks[0].Output)
+}
+
+func TestDefaultTimeoutWithoutDeadline(t *testing.T) {
+	defer func(timeout time.Duration) { defaultTimeout = timeout }(defaultTimeout)
+	defaultTimeout = 10 * time.Millisecond
 
-	assert.Equal(t, time.Duration(0), client.Timeout)
+	var check health.ContextChecker = Check{
+		HttpClient:   http.Client{Transport: blockingRoundTripper{}},
+		MustPassURLs: []string{longDelayURL},
+	}
 
-	clone := clientWithDefaultTimeout(client)
+	checks, status := check.CheckContext(context.Background())
 
-	assert.Equal(t, time.Duration(0), client.Timeout)
-	assert.Equal(t, defaultTimeout, clone.Timeout)
-	assert.Equal(t, client.Transport, clone.Transport)
+	assert.Equal(t, health.Fail, status)
+	assert.Equal(t, 1, len(checks))
+	assert.Equal(t, health.Key{ComponentName: longDelayURL, MeasurementName: statusMeasurementName}, checks[0].Key)
+	assert.Regexp(t, "timed out|"+context.DeadlineExceeded.Error(), checks[0].Output)
 }
@@ -0,0 +1,118 @@
+// Package grpchealth implements the gRPC Health Checking Protocol
+// (grpc.health.v1.Health) using the same Checkers that back the HTTP
+// Handler in the health package.
+//
+// See - https://github.com/grpc/grpc/blob/master/doc/health-checking.md
+package grpchealth
+
+import (
+	"context"
+	"time"
+
+	"github.com/PennState/go-healthcheck/pkg/health"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+const defaultInterval = 5 * time.Second
+
+// Server is a grpc_health_v1.HealthServer that reports the health of
+// each gRPC service by executing its Checker.  A Pass or Warn Status is
+// reported as SERVING, any other Status as NOT_SERVING.
+//
+// Register the Server with grpc_health_v1.RegisterHealthServer.
+type Server struct {
+	// Services maps the gRPC service names to the Checkers reporting
+	// their health.  By convention, the empty name reports the health of
+	// the server as a whole.  Services must not be modified once the
+	// Server is registered.
+	Services map[string]health.Checker
+	// Timeout bounds each execution of a Checker.  A zero Timeout relies
+	// solely on the deadline of the RPC.
+	Timeout time.Duration
+	// Interval is the time between executions of a Checker while its
+	// service is being watched.  Defaults to five seconds.
+	Interval time.Duration
+}
+
+// NewServer returns a Server reporting the health of the server as a
+// whole using every Checker in the Registry and, for each provided
+// service name, the health of the service using the Checkers tagged with
+// its name.
+func NewServer(registry *health.Registry, services ...string) *Server {
+	s := &Server{Services: map[string]health.Checker{"": registry}}
+	for _, service := range services {
+		s.Services[service] = registry.Tagged(service)
+	}
+	return s
+}
+
+// Check executes the requested service's Checker.  A NotFound error is
+// returned if the service is unknown.
+func (s *Server) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	checker, ok := s.Services[req.GetService()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown service: %v", req.GetService())
+	}
+	return &healthpb.HealthCheckResponse{Status: s.check(ctx, req.GetService(), checker)}, nil
+}
+
+// Watch periodically executes the requested service's Checker, sending
+// its initial status and then each change to that status.  An unknown
+// service is reported as SERVICE_UNKNOWN.
+func (s *Server) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ctx := stream.Context()
+	checker, ok := s.Services[req.GetService()]
+
+	interval := s.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := healthpb.HealthCheckResponse_UNKNOWN
+	for {
+		current := healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+		if ok {
+			current = s.check(ctx, req.GetService(), checker)
+		}
+		if current != last && ctx.Err() == nil {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: current}); err != nil {
+				return err
+			}
+			last = current
+		}
+		if !ok {
+			// The Services can't change, so neither can the status
+			<-ctx.Done()
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Server) check(ctx context.Context, service string, checker health.Checker) healthpb.HealthCheckResponse_ServingStatus {
+	tc := health.TimeoutChecker{
+		Key:     health.Key{ComponentName: service},
+		Checker: checker,
+		Timeout: s.Timeout,
+	}
+	_, st := tc.CheckContext(ctx)
+	return ServingStatus(st)
+}
+
+// ServingStatus maps the Status to the gRPC serving status.
+func ServingStatus(s health.Status) healthpb.HealthCheckResponse_ServingStatus {
+	switch s {
+	case health.Pass, health.Warn:
+		return healthpb.HealthCheckResponse_SERVING
+	default:
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	}
+}
@@ -0,0 +1,121 @@
+package grpchealth
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PennState/go-healthcheck/pkg/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type statusChecker struct {
+	status *int32
+}
+
+func (s statusChecker) Check() ([]health.ComponentDetail, health.Status) {
+	st := health.Status(atomic.LoadInt32(s.status))
+	return []health.ComponentDetail{{Key: health.Key{ComponentName: "toggle"}, Status: st}}, st
+}
+
+func constant(st health.Status) health.Checker {
+	s := int32(st)
+	return statusChecker{status: &s}
+}
+
+func dial(t *testing.T, s *Server) (healthpb.HealthClient, func()) {
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, s)
+	go srv.Serve(lis)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithInsecure(),
+	)
+	require.NoError(t, err)
+	return healthpb.NewHealthClient(conn), func() {
+		conn.Close()
+		srv.Stop()
+	}
+}
+
+func TestCheck(t *testing.T) {
+	var r health.Registry
+	require.NoError(t, r.Register(health.Registration{Name: "db", Checker: constant(health.Fail), Tags: []string{"orders.Orders"}}))
+	require.NoError(t, r.Register(health.Registration{Name: "cache", Checker: constant(health.Warn), Tags: []string{"catalog.Catalog"}}))
+	client, stop := dial(t, NewServer(&r, "orders.Orders", "catalog.Catalog"))
+	defer stop()
+	ctx := context.Background()
+
+	resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "catalog.Catalog"})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	resp, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "orders.Orders"})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+
+	resp, err = client.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestWatch(t *testing.T) {
+	current := int32(health.Pass)
+	s := &Server{
+		Services: map[string]health.Checker{"": statusChecker{status: &current}},
+		Interval: 5 * time.Millisecond,
+	}
+	client, stop := dial(t, s)
+	defer stop()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	atomic.StoreInt32(&current, int32(health.Fail))
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+
+	atomic.StoreInt32(&current, int32(health.Warn))
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+}
+
+func TestWatchUnknownService(t *testing.T) {
+	client, stop := dial(t, &Server{})
+	defer stop()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "missing"})
+	require.NoError(t, err)
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVICE_UNKNOWN, resp.Status)
+}
+
+func TestServingStatus(t *testing.T) {
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, ServingStatus(health.Pass))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, ServingStatus(health.Warn))
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, ServingStatus(health.Undetermined))
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, ServingStatus(health.Fail))
+}
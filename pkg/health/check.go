@@ -1,4 +1,43 @@
 package health
 
+import (
+	"context"
+	"fmt"
+)
+
 // Check types define a function that return Checks objects when executed.
 type Check func() Checks
+
+// ContextChecker is a Checker that honors the cancellation and deadline of
+// the provided context.
+type ContextChecker interface {
+	Checker
+	CheckContext(ctx context.Context) ([]ComponentDetail, Status)
+}
+
+// WithContext adapts a Checker into a ContextChecker.  Checkers that already
+// implement ContextChecker are returned unchanged, otherwise the returned
+// ContextChecker ignores the context and simply calls Check.
+func WithContext(checker Checker) ContextChecker {
+	if cc, ok := checker.(ContextChecker); ok {
+		return cc
+	}
+	return contextAdapter{checker}
+}
+
+type contextAdapter struct {
+	Checker
+}
+
+func (c contextAdapter) CheckContext(ctx context.Context) ([]ComponentDetail, Status) {
+	return c.Check()
+}
+
+// defaultKey provides the Key used for ComponentDetail objects that are
+// synthesized on behalf of a Checker that didn't provide its own.
+func defaultKey(checker Checker) Key {
+	if ca, ok := checker.(contextAdapter); ok {
+		checker = ca.Checker
+	}
+	return Key{ComponentName: fmt.Sprintf("%T", checker)}
+}
@@ -0,0 +1,114 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// Client retrieves and decodes Health documents from remote health
+// endpoints.
+//
+// The zero value is a Client that uses http.DefaultClient.
+type Client struct {
+	HTTPClient *http.Client
+	// Header is added to each request (e.g. for authorization).
+	Header http.Header
+}
+
+// TransportError is returned by Client when the health endpoint couldn't
+// be reached or its response couldn't be read.
+type TransportError struct {
+	URL string
+	Err error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("Unable to retrieve health from %s: %v", e.URL, e.Err)
+}
+
+// Unwrap returns the underlying error (e.g. context.DeadlineExceeded).
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeError is returned by Client when the health endpoint responded
+// with a document that isn't a valid Health document, including one
+// without the status the RFC requires.
+type DecodeError struct {
+	URL        string
+	StatusCode int
+	Body       []byte
+	Err        error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("Unable to decode health from %s (HTTP %d): %v", e.URL, e.StatusCode, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Get retrieves the Health document at url.
+func (c Client) Get(url string) (Health, error) {
+	return c.GetContext(context.Background(), url)
+}
+
+// GetContext retrieves the Health document at url.  Non-2xx responses are
+// decoded like any other since, for example, a 503 is the expected
+// response from a failing service.  A TransportError or DecodeError is
+// returned if the document couldn't be retrieved or decoded.
+func (c Client) GetContext(ctx context.Context, url string) (Health, error) {
+	var health Health
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return health, &TransportError{URL: url, Err: err}
+	}
+	for k, v := range c.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Accept", ContentType+", application/json;q=0.9")
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return health, &TransportError{URL: url, Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return health, &TransportError{URL: url, Err: err}
+	}
+
+	err = decodeHealth(body, &health)
+	if err != nil {
+		return health, &DecodeError{URL: url, StatusCode: resp.StatusCode, Body: body, Err: err}
+	}
+	return health, nil
+}
+
+// decodeHealth decodes the Health document, which must have a status since
+// otherwise any JSON object (e.g. a proxy's error page) would decode as
+// Pass.
+func decodeHealth(data []byte, health *Health) error {
+	var required struct {
+		Status json.RawMessage `json:"status"`
+	}
+	err := json.Unmarshal(data, &required)
+	if err != nil {
+		return err
+	}
+	if len(required.Status) == 0 || string(required.Status) == "null" {
+		return fmt.Errorf("Health document is missing a status")
+	}
+	return json.Unmarshal(data, health)
+}
@@ -0,0 +1,97 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientDecodesHealth(t *testing.T) {
+	assert := assert.New(t)
+	key := Key{ComponentName: "db", MeasurementName: "responseTime"}
+	handler := NewHandler(Service{Version: "1"}, detailChecker("db", Pass), testChecker{
+		details: []ComponentDetail{{Key: key, ObservedValue: float64(250), ObservedUnit: "ms", Status: Pass}},
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(r.Header.Get("Accept"), ContentType)
+		assert.Equal("token", r.Header.Get("Authorization"))
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client := Client{Header: http.Header{"Authorization": []string{"token"}}}
+	h, err := client.Get(server.URL)
+
+	require.NoError(t, err)
+	assert.Equal(Pass, h.Status)
+	assert.Equal("1", h.Version)
+	require.Len(t, h.Checks[key], 1)
+	assert.Equal(float64(250), h.Checks[key][0].ObservedValue)
+}
+
+func TestClientDecodesFailingHealth(t *testing.T) {
+	server := httptest.NewServer(NewHandler(Service{}, detailChecker("db", Fail)))
+	defer server.Close()
+
+	h, err := Client{}.Get(server.URL)
+
+	require.NoError(t, err)
+	assert.Equal(t, Fail, h.Status)
+	assert.Equal(t, Fail, h.Checks[Key{ComponentName: "db"}][0].Status)
+}
+
+func TestClientReturnsDecodeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(`{"status": "sideways"}`))
+	}))
+	defer server.Close()
+
+	_, err := Client{}.Get(server.URL)
+
+	require.Error(t, err)
+	decodeErr, ok := err.(*DecodeError)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusBadGateway, decodeErr.StatusCode)
+	assert.Equal(t, `{"status": "sideways"}`, string(decodeErr.Body))
+}
+
+func TestClientReturnsTransportError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := Client{}.GetContext(ctx, server.URL)
+
+	require.Error(t, err)
+	_, ok := err.(*TransportError)
+	assert.True(t, ok)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestClientRequiresStatus(t *testing.T) {
+	for _, body := range []string{`{}`, `{"message": "no healthy upstream"}`, `{"status": null}`} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(body))
+		}))
+
+		_, err := Client{}.Get(server.URL)
+		server.Close()
+
+		require.Error(t, err, body)
+		decodeErr, ok := err.(*DecodeError)
+		require.True(t, ok, body)
+		assert.Equal(t, http.StatusServiceUnavailable, decodeErr.StatusCode)
+		assert.Contains(t, err.Error(), "missing a status")
+	}
+}
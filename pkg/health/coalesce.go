@@ -0,0 +1,148 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const defaultCoalescedTimeout = 30 * time.Second
+
+// Coalescer protects an expensive Checker from concurrent and repeated
+// execution.  Evaluations that arrive while the Checker is executing wait
+// for, and share, the results of that execution rather than starting
+// their own.  Evaluations that arrive within MinInterval of the previous
+// execution starting reuse its results, including their original Time.
+//
+// The shared execution isn't bound by the context of any evaluation, so
+// an evaluation that gives up waiting (e.g. because its request timed out)
+// doesn't affect the others.  It's instead bound by Timeout, and results
+// produced by an execution that timed out aren't reused.  The Checker is
+// always executed without any Filter carried by the context, so the shared
+// results are complete.
+//
+// A Coalescer must be used through a pointer and should not be copied
+// after first use.
+type Coalescer struct {
+	// Key identifies the ComponentDetail synthesized when the Checker
+	// panics, returns no results or an evaluation stops waiting for it.
+	// If it's empty, a Key derived from the Checker's type is used.
+	Key     Key
+	Checker Checker
+	// MinInterval is the minimum duration between executions of the
+	// Checker.  A zero MinInterval only coalesces concurrent evaluations.
+	MinInterval time.Duration
+	// Timeout bounds each execution of the Checker.  Defaults to thirty
+	// seconds.
+	Timeout time.Duration
+	// Warn reports a timeout as Warn rather than Fail.
+	Warn bool
+
+	mu       sync.Mutex
+	inFlight *coalescedCall
+	last     *coalescedCall
+}
+
+type coalescedCall struct {
+	done    chan struct{}
+	start   time.Time
+	details []ComponentDetail
+	status  Status
+}
+
+// Check executes the Checker with a background context.
+func (c *Coalescer) Check() ([]ComponentDetail, Status) {
+	return c.CheckContext(context.Background())
+}
+
+// CheckContext returns the results of the previous execution if it's
+// recent enough, otherwise the results of an execution of the Checker
+// that's shared with any concurrent evaluations.
+func (c *Coalescer) CheckContext(ctx context.Context) ([]ComponentDetail, Status) {
+	c.mu.Lock()
+	if c.last != nil && time.Since(c.last.start) < c.MinInterval {
+		last := c.last
+		c.mu.Unlock()
+		return last.details, last.status
+	}
+	call := c.inFlight
+	if call == nil {
+		call = &coalescedCall{done: make(chan struct{}), start: time.Now().UTC()}
+		c.inFlight = call
+		go c.execute(detach(withoutFilter(ctx)), call)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.details, call.status
+	case <-ctx.Done():
+		return TimeoutChecker{Key: c.key(), Warn: c.Warn}.timedOut(ctx.Err(), call.start)
+	}
+}
+
+// execute runs the Checker on behalf of every evaluation waiting for the
+// call.
+func (c *Coalescer) execute(ctx context.Context, call *coalescedCall) {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = defaultCoalescedTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	details, status := TimeoutChecker{Key: c.key(), Checker: c.Checker, Warn: c.Warn}.CheckContext(ctx)
+	call.details = make([]ComponentDetail, len(details))
+	for i, detail := range details {
+		if detail.Time.IsZero() {
+			detail.Time = call.start
+		}
+		call.details[i] = detail
+	}
+	call.status = status
+
+	c.mu.Lock()
+	c.inFlight = nil
+	if ctx.Err() == nil {
+		c.last = call
+	}
+	c.mu.Unlock()
+	close(call.done)
+}
+
+func (c *Coalescer) key() Key {
+	if c.Key == (Key{}) {
+		return defaultKey(c.Checker)
+	}
+	return c.Key
+}
+
+func (c *Coalescer) aggregates() bool {
+	return aggregates(c.Checker)
+}
+
+// detachedContext carries the values of its parent without being done when
+// the parent is.
+type detachedContext struct {
+	parent context.Context
+}
+
+func detach(ctx context.Context) context.Context {
+	return detachedContext{parent: ctx}
+}
+
+func (d detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (d detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (d detachedContext) Err() error {
+	return nil
+}
+
+func (d detachedContext) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
@@ -0,0 +1,165 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type gatedChecker struct {
+	runs    *int32
+	release chan struct{}
+}
+
+func (g gatedChecker) Check() ([]ComponentDetail, Status) {
+	atomic.AddInt32(g.runs, 1)
+	<-g.release
+	return []ComponentDetail{{Key: Key{ComponentName: "gated"}}}, Warn
+}
+
+func TestCoalescerSharesConcurrentExecution(t *testing.T) {
+	var runs int32
+	release := make(chan struct{})
+	c := &Coalescer{Checker: gatedChecker{runs: &runs, release: release}}
+
+	var wg sync.WaitGroup
+	statuses := make([]Status, 10)
+	for i := range statuses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, statuses[i] = c.Check()
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&runs))
+	for _, status := range statuses {
+		assert.Equal(t, Warn, status)
+	}
+}
+
+func TestCoalescerReusesResultsWithinMinInterval(t *testing.T) {
+	var runs int32
+	c := &Coalescer{
+		Checker:     runCounter{key: Key{ComponentName: "scan"}, runs: &runs},
+		MinInterval: 50 * time.Millisecond,
+	}
+
+	first, _ := c.Check()
+	second, _ := c.Check()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&runs))
+	require.Len(t, second, 1)
+	assert.False(t, first[0].Time.IsZero())
+	assert.Equal(t, first[0].Time, second[0].Time)
+
+	time.Sleep(60 * time.Millisecond)
+	third, _ := c.Check()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&runs))
+	assert.True(t, third[0].Time.After(first[0].Time))
+}
+
+func TestCoalescerWaiterHonoursContext(t *testing.T) {
+	var runs int32
+	release := make(chan struct{})
+	defer close(release)
+	c := &Coalescer{Key: Key{ComponentName: "gated"}, Checker: gatedChecker{runs: &runs, release: release}}
+
+	go c.Check()
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	details, status := c.CheckContext(ctx)
+	assert.Equal(t, Fail, status)
+	require.Len(t, details, 1)
+	assert.Equal(t, Key{ComponentName: "gated"}, details[0].Key)
+	assert.Contains(t, details[0].Output, "Check timed out after")
+}
+
+func TestCoalescerSharesPanic(t *testing.T) {
+	c := &Coalescer{Key: Key{ComponentName: "broken"}, Checker: panickingChecker{}, MinInterval: time.Minute}
+
+	details, status := c.Check()
+	assert.Equal(t, Fail, status)
+	require.Len(t, details, 1)
+	assert.Equal(t, Key{ComponentName: "broken"}, details[0].Key)
+
+	again, _ := c.Check()
+	assert.Equal(t, details, again)
+}
+
+func TestRegistryMinInterval(t *testing.T) {
+	var runs int32
+	var r Registry
+	require.NoError(t, r.Register(Registration{
+		Name:        "scan",
+		Checker:     runCounter{key: Key{ComponentName: "scan"}, runs: &runs},
+		MinInterval: time.Minute,
+	}))
+
+	r.Check()
+	r.Check()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&runs))
+}
+
+func TestCoalescerIsNotBoundByCallerContext(t *testing.T) {
+	var runs int32
+	c := &Coalescer{
+		Checker:     contextChecker{delay: 20 * time.Millisecond, runs: &runs},
+		MinInterval: time.Minute,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	_, status := c.CheckContext(ctx)
+	assert.Equal(t, Fail, status)
+
+	details, status := c.Check()
+	assert.Equal(t, Pass, status)
+	require.Len(t, details, 1)
+	assert.Empty(t, details[0].Output)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&runs))
+}
+
+func TestCoalescerDoesNotReuseTimedOutResults(t *testing.T) {
+	var runs int32
+	c := &Coalescer{
+		Checker:     contextChecker{delay: time.Second, runs: &runs},
+		MinInterval: time.Minute,
+		Timeout:     5 * time.Millisecond,
+	}
+
+	_, status := c.Check()
+	assert.Equal(t, Fail, status)
+	_, status = c.Check()
+	assert.Equal(t, Fail, status)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&runs))
+}
+
+// contextChecker passes unless its context is done before the delay.
+type contextChecker struct {
+	delay time.Duration
+	runs  *int32
+}
+
+func (c contextChecker) Check() ([]ComponentDetail, Status) {
+	return c.CheckContext(context.Background())
+}
+
+func (c contextChecker) CheckContext(ctx context.Context) ([]ComponentDetail, Status) {
+	atomic.AddInt32(c.runs, 1)
+	select {
+	case <-time.After(c.delay):
+		return []ComponentDetail{{Key: Key{ComponentName: "scan"}}}, Pass
+	case <-ctx.Done():
+		return []ComponentDetail{{Key: Key{ComponentName: "scan"}, Status: Fail, Output: ctx.Err().Error()}}, Fail
+	}
+}
@@ -0,0 +1,166 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Composite combines the results of its Checkers into a single Status.
+// The Checkers are executed concurrently and the composite Status is the
+// most severe Status that is matched or bettered by at least Quorum of
+// them.  For example, with a Quorum of 2 and Checkers reporting Pass, Warn
+// and Fail, the composite Status is Warn.
+//
+// The Checkers' results are reported unchanged, along with a
+// ComponentDetail under Key that reports the composite Status and explains
+// how it was reached.  If Key is empty, only the Checkers' results are
+// reported.
+type Composite struct {
+	Key      Key
+	Checkers []Checker
+	// Quorum is the number of Checkers that must report a Status for it to
+	// become the composite Status.  A Quorum that isn't positive requires
+	// every Checker.  A Quorum larger than the number of Checkers can never
+	// be met and results in Fail.
+	Quorum int
+}
+
+// AllOf returns a Composite that reports the most severe Status of the
+// checkers (i.e. all of them must pass for the Composite to pass).
+func AllOf(key Key, checkers ...Checker) Composite {
+	return Composite{Key: key, Checkers: checkers, Quorum: len(checkers)}
+}
+
+// AnyOf returns a Composite that reports the least severe Status of the
+// checkers (i.e. any of them passing is enough for the Composite to pass),
+// as is appropriate for redundant replicas.
+func AnyOf(key Key, checkers ...Checker) Composite {
+	return Composite{Key: key, Checkers: checkers, Quorum: 1}
+}
+
+// Quorum returns a Composite that passes when at least k of the checkers
+// pass.
+func Quorum(key Key, k int, checkers ...Checker) Composite {
+	return Composite{Key: key, Checkers: checkers, Quorum: k}
+}
+
+// Check executes the Checkers with a background context.
+func (c Composite) Check() ([]ComponentDetail, Status) {
+	return c.CheckContext(context.Background())
+}
+
+// CheckContext concurrently executes the Checkers and combines their
+// results.
+func (c Composite) CheckContext(ctx context.Context) ([]ComponentDetail, Status) {
+	start := time.Now().UTC()
+	checkers := make([]TimeoutChecker, len(c.Checkers))
+	for i, checker := range c.Checkers {
+		tc, ok := checker.(TimeoutChecker)
+		if !ok {
+			tc = TimeoutChecker{Key: defaultKey(checker), Checker: checker}
+		}
+		checkers[i] = tc
+	}
+
+	var details []ComponentDetail
+	statuses := make([]Status, 0, len(checkers))
+	for _, r := range runEach(withoutFilter(ctx), 0, checkers...) {
+		details = append(details, r.details...)
+		statuses = append(statuses, r.status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Severity() < statuses[j].Severity()
+	})
+
+	quorum := c.Quorum
+	if quorum <= 0 {
+		quorum = len(statuses)
+	}
+	status := Fail
+	if quorum <= len(statuses) {
+		status = Pass
+		if quorum > 0 {
+			status = statuses[quorum-1]
+		}
+	}
+
+	if c.Key == (Key{}) {
+		return details, status
+	}
+	summary := ComponentDetail{
+		Key:           c.Key,
+		ComponentType: "component",
+		Status:        status,
+		Time:          start,
+		Output:        fmt.Sprintf("Requires %d of %d checks: %s", quorum, len(statuses), countStatuses(statuses)),
+	}
+	return append([]ComponentDetail{summary}, details...), status
+}
+
+func (c Composite) aggregates() bool {
+	return true
+}
+
+// countStatuses describes the number of each Status (e.g. "2 pass, 1 fail").
+func countStatuses(statuses []Status) string {
+	counts := make([]int, len(statusData))
+	for _, s := range statuses {
+		if s.valid() {
+			counts[s]++
+		}
+	}
+	var parts []string
+	for i, n := range counts {
+		if n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, strings.ToLower(Status(i).String())))
+		}
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// WithCriticality returns a Checker that maps the Status of the provided
+// checker through the Criticality (e.g. NonCritical caps it at Warn).  Each
+// ComponentDetail whose Status is changed by the mapping is reported with
+// the mapped Status, an Output explaining the change and the original
+// Status retained in its "rawStatus" additional property.
+func WithCriticality(checker Checker, criticality Criticality) ContextChecker {
+	return criticalityChecker{checker: WithContext(checker), criticality: criticality}
+}
+
+type criticalityChecker struct {
+	checker     ContextChecker
+	criticality Criticality
+}
+
+func (c criticalityChecker) Check() ([]ComponentDetail, Status) {
+	return c.CheckContext(context.Background())
+}
+
+func (c criticalityChecker) CheckContext(ctx context.Context) ([]ComponentDetail, Status) {
+	details, status := c.checker.CheckContext(ctx)
+	explained := make([]ComponentDetail, len(details))
+	for i, d := range details {
+		if mapped := c.criticality.Apply(d.Status); mapped != d.Status {
+			output := fmt.Sprintf("%s reported as %s by criticality", strings.ToLower(d.Status.String()), strings.ToLower(mapped.String()))
+			if d.Output == "" {
+				d.Output = output
+			} else {
+				d.Output = output + ": " + d.Output
+			}
+			d = d.withProperty(rawStatusProperty, d.Status)
+			d.Status = mapped
+		}
+		explained[i] = d
+	}
+	return explained, c.criticality.Apply(status)
+}
+
+func (c criticalityChecker) aggregates() bool {
+	return aggregates(c.checker)
+}
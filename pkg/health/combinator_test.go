@@ -0,0 +1,110 @@
+package health
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var compositeKey = Key{ComponentName: "replicas"}
+
+func replicas(statuses ...Status) []Checker {
+	var checkers []Checker
+	for i, s := range statuses {
+		checkers = append(checkers, detailChecker(string(rune('a'+i)), s))
+	}
+	return checkers
+}
+
+func TestAllOf(t *testing.T) {
+	_, status := AllOf(compositeKey, replicas(Pass, Pass)...).Check()
+	assert.Equal(t, Pass, status)
+
+	details, status := AllOf(compositeKey, replicas(Pass, Warn, Fail)...).Check()
+	assert.Equal(t, Fail, status)
+	require.Len(t, details, 4)
+	assert.Equal(t, compositeKey, details[0].Key)
+	assert.Equal(t, Fail, details[0].Status)
+	assert.Equal(t, "Requires 3 of 3 checks: 1 pass, 1 warn, 1 fail", details[0].Output)
+}
+
+func TestAnyOf(t *testing.T) {
+	_, status := AnyOf(compositeKey, replicas(Fail, Pass, Fail)...).Check()
+	assert.Equal(t, Pass, status)
+
+	_, status = AnyOf(compositeKey, replicas(Fail, Warn)...).Check()
+	assert.Equal(t, Warn, status)
+
+	_, status = AnyOf(compositeKey, replicas(Fail, Fail)...).Check()
+	assert.Equal(t, Fail, status)
+}
+
+func TestQuorum(t *testing.T) {
+	assert := assert.New(t)
+
+	_, status := Quorum(compositeKey, 2, replicas(Pass, Fail, Pass)...).Check()
+	assert.Equal(Pass, status)
+
+	_, status = Quorum(compositeKey, 2, replicas(Pass, Warn, Fail)...).Check()
+	assert.Equal(Warn, status)
+
+	details, status := Quorum(compositeKey, 2, replicas(Pass, Fail, Fail)...).Check()
+	assert.Equal(Fail, status)
+	assert.Len(details, 4)
+
+	details, status = Quorum(compositeKey, 4, replicas(Pass, Pass, Pass)...).Check()
+	assert.Equal(Fail, status)
+	assert.Contains(details[0].Output, "Requires 4 of 3")
+}
+
+func TestCompositeIgnoresFilterForChildren(t *testing.T) {
+	ctx := WithFilter(context.Background(), Filter{Components: []string{"a"}})
+
+	details, status := AllOf(compositeKey, replicas(Pass, Fail)...).CheckContext(ctx)
+
+	assert.Equal(t, Fail, status)
+	assert.Len(t, details, 3)
+}
+
+func TestCompositeIsolatesChildren(t *testing.T) {
+	details, status := AnyOf(compositeKey, panickingChecker{}, detailChecker("b", Pass)).Check()
+
+	assert.Equal(t, Pass, status)
+	require.Len(t, details, 3)
+	assert.Contains(t, details[1].Output, "panicked")
+}
+
+func TestWithCriticality(t *testing.T) {
+	assert := assert.New(t)
+	checker := testChecker{
+		details: []ComponentDetail{
+			{Key: Key{ComponentName: "a"}, Status: Fail, Output: "connection refused"},
+			{Key: Key{ComponentName: "b"}, Status: Pass},
+		},
+		status: Fail,
+	}
+
+	details, status := WithCriticality(checker, NonCritical).Check()
+
+	assert.Equal(Warn, status)
+	require.Len(t, details, 2)
+	assert.Equal(Warn, details[0].Status)
+	assert.Equal(Fail, details[0].AdditionalProperties[rawStatusProperty])
+	assert.Equal("fail reported as warn by criticality: connection refused", details[0].Output)
+	assert.Empty(details[1].Output)
+	assert.Nil(details[1].AdditionalProperties)
+	assert.Nil(checker.details[0].AdditionalProperties)
+
+	_, status = WithCriticality(checker, Informational).Check()
+	assert.Equal(Pass, status)
+}
+
+func TestCompositeWithoutKeyOmitsSummary(t *testing.T) {
+	details, status := AllOf(Key{}, detailChecker("a", Pass), detailChecker("b", Warn)).Check()
+
+	assert.Equal(t, Warn, status)
+	assert.Equal(t, []Key{{ComponentName: "a"}, {ComponentName: "b"}}, []Key{details[0].Key, details[1].Key})
+	assert.Len(t, details, 2)
+}
@@ -0,0 +1,27 @@
+package health
+
+// Criticality describes how much the Status of a dependency is allowed to
+// affect the Status of the service that depends on it.
+type Criticality int
+
+const (
+	// Critical dependencies report their Status unchanged.
+	Critical Criticality = iota
+	// NonCritical dependencies report at most Warn.
+	NonCritical
+	// Informational dependencies always report Pass.
+	Informational
+)
+
+// Apply maps the Status of a dependency to the Status reported for it.
+func (c Criticality) Apply(s Status) Status {
+	switch c {
+	case NonCritical:
+		if s.Severity() > Warn.Severity() {
+			return Warn
+		}
+	case Informational:
+		return Pass
+	}
+	return s
+}
@@ -0,0 +1,108 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// rawStatusProperty is the additional property that retains the Status
+// actually observed for a ComponentDetail whose Status was changed.
+const rawStatusProperty = "rawStatus"
+
+// Debouncer suppresses flapping of its Checker's Status.  Fail (or
+// Undetermined) is only reported after FailureThreshold consecutive
+// failing executions and, once failing, Pass (or Warn) is only reported
+// after SuccessThreshold consecutive non-failing executions.  While a transition is pending,
+// the affected ComponentDetail objects are reported as Warn with the
+// pending transition explained in their Output and the observed Status
+// retained in their "rawStatus" additional property.
+//
+// A Debouncer must be used through a pointer and should not be copied
+// after first use.
+type Debouncer struct {
+	Checker          Checker
+	FailureThreshold int
+	SuccessThreshold int
+
+	mu        sync.Mutex
+	failing   bool
+	failures  int
+	successes int
+}
+
+// Check executes the Checker with a background context.
+func (d *Debouncer) Check() ([]ComponentDetail, Status) {
+	return d.CheckContext(context.Background())
+}
+
+// CheckContext executes the Checker and reports its results according to
+// the Debouncer's history.
+func (d *Debouncer) CheckContext(ctx context.Context) ([]ComponentDetail, Status) {
+	details, raw := WithContext(d.Checker).CheckContext(ctx)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if isFailure(raw) {
+		d.successes = 0
+		d.failures++
+		if d.failing || d.failures >= d.FailureThreshold {
+			d.failing = true
+			return details, raw
+		}
+		output := fmt.Sprintf("Failed %d of %d consecutive checks required to report fail", d.failures, d.FailureThreshold)
+		return rewriteStatus(details, Warn, output, Fail, Undetermined), Warn
+	}
+
+	d.failures = 0
+	if !d.failing {
+		return details, raw
+	}
+	d.successes++
+	if d.successes >= d.SuccessThreshold {
+		d.failing, d.successes = false, 0
+		return details, raw
+	}
+	output := fmt.Sprintf("Passed %d of %d consecutive checks required to recover", d.successes, d.SuccessThreshold)
+	return rewriteStatus(details, Warn, output, Pass), Warn
+}
+
+// isFailure returns true if the Status counts as a failure, which includes
+// Undetermined since the service isn't known to be healthy.
+func isFailure(s Status) bool {
+	return s.Severity() >= Undetermined.Severity()
+}
+
+func (d *Debouncer) aggregates() bool {
+	return aggregates(d.Checker)
+}
+
+// rewriteStatus reports the details whose Status is one of from as to,
+// explaining the change in their Output and retaining their original
+// Status as an additional property.
+func rewriteStatus(details []ComponentDetail, to Status, output string, from ...Status) []ComponentDetail {
+	rewritten := make([]ComponentDetail, len(details))
+	for i, detail := range details {
+		if hasStatus(detail.Status, from...) {
+			detail = detail.withProperty(rawStatusProperty, detail.Status)
+			detail.Status = to
+			if detail.Output == "" {
+				detail.Output = output
+			} else {
+				detail.Output = output + ": " + detail.Output
+			}
+		}
+		rewritten[i] = detail
+	}
+	return rewritten
+}
+
+func hasStatus(s Status, statuses ...Status) bool {
+	for _, status := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
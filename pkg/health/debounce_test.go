@@ -0,0 +1,134 @@
+package health
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sequenceChecker struct {
+	statuses []Status
+	next     *int
+}
+
+func (s sequenceChecker) Check() ([]ComponentDetail, Status) {
+	status := s.statuses[*s.next]
+	*s.next++
+	return []ComponentDetail{{Key: Key{ComponentName: "db"}, Status: status}}, status
+}
+
+func debounced(statuses ...Status) *Debouncer {
+	return &Debouncer{
+		Checker:          sequenceChecker{statuses: statuses, next: new(int)},
+		FailureThreshold: 3,
+		SuccessThreshold: 2,
+	}
+}
+
+func TestDebouncerSuppressesTransientFailures(t *testing.T) {
+	assert := assert.New(t)
+	d := debounced(Pass, Fail, Fail, Pass, Warn)
+
+	_, status := d.Check()
+	assert.Equal(Pass, status)
+
+	details, status := d.Check()
+	assert.Equal(Warn, status)
+	require.Len(t, details, 1)
+	assert.Equal(Warn, details[0].Status)
+	assert.Equal(Fail, details[0].AdditionalProperties[rawStatusProperty])
+	assert.Contains(details[0].Output, "Failed 1 of 3")
+
+	details, status = d.Check()
+	assert.Equal(Warn, status)
+	assert.Contains(details[0].Output, "Failed 2 of 3")
+
+	details, status = d.Check()
+	assert.Equal(Pass, status)
+	assert.Nil(details[0].AdditionalProperties)
+
+	_, status = d.Check()
+	assert.Equal(Warn, status)
+}
+
+func TestDebouncerFailsAndRecovers(t *testing.T) {
+	assert := assert.New(t)
+	d := debounced(Fail, Fail, Fail, Pass, Fail, Pass, Pass, Pass)
+
+	d.Check()
+	d.Check()
+	details, status := d.Check()
+	assert.Equal(Fail, status)
+	assert.Equal(Fail, details[0].Status)
+
+	details, status = d.Check()
+	assert.Equal(Warn, status)
+	assert.Equal(Warn, details[0].Status)
+	assert.Equal(Pass, details[0].AdditionalProperties[rawStatusProperty])
+	assert.Contains(details[0].Output, "Passed 1 of 2")
+
+	_, status = d.Check()
+	assert.Equal(Fail, status)
+
+	_, status = d.Check()
+	assert.Equal(Warn, status)
+	_, status = d.Check()
+	assert.Equal(Pass, status)
+	_, status = d.Check()
+	assert.Equal(Pass, status)
+}
+
+func TestDebouncerWithoutThresholds(t *testing.T) {
+	d := &Debouncer{Checker: sequenceChecker{statuses: []Status{Fail, Pass}, next: new(int)}}
+
+	_, status := d.Check()
+	assert.Equal(t, Fail, status)
+	_, status = d.Check()
+	assert.Equal(t, Pass, status)
+}
+
+func TestDebouncerOnlyRewritesFailingDetails(t *testing.T) {
+	assert := assert.New(t)
+	d := &Debouncer{
+		Checker: testChecker{
+			details: []ComponentDetail{
+				{Key: Key{ComponentName: "db", MeasurementName: "connections"}, Status: Fail},
+				{Key: Key{ComponentName: "db", MeasurementName: "latency"}, Status: Pass},
+			},
+			status: Fail,
+		},
+		FailureThreshold: 3,
+	}
+
+	details, status := d.Check()
+	assert.Equal(Warn, status)
+	require.Len(t, details, 2)
+	assert.Equal(Warn, details[0].Status)
+	assert.Equal(Pass, details[1].Status)
+	assert.Empty(details[1].Output)
+	assert.Nil(details[1].AdditionalProperties)
+}
+
+func TestDebouncerTreatsUndeterminedAsFailure(t *testing.T) {
+	assert := assert.New(t)
+	d := &Debouncer{
+		Checker:          sequenceChecker{statuses: []Status{Undetermined, Undetermined, Fail, Undetermined}, next: new(int)},
+		FailureThreshold: 2,
+		SuccessThreshold: 1,
+	}
+
+	details, status := d.Check()
+	assert.Equal(Warn, status)
+	assert.Equal(Warn, details[0].Status)
+	assert.Equal(Undetermined, details[0].AdditionalProperties[rawStatusProperty])
+
+	_, status = d.Check()
+	assert.Equal(Undetermined, status)
+	_, status = d.Check()
+	assert.Equal(Fail, status)
+
+	// Undetermined doesn't count towards recovery
+	_, status = d.Check()
+	assert.Equal(Undetermined, status)
+}
@@ -0,0 +1,157 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// findCycle returns the names forming a dependency cycle through the named
+// registration, or nil if there isn't one.
+func findCycle(registrations []Registration, name string) []string {
+	byName := map[string]Registration{}
+	for _, reg := range registrations {
+		byName[reg.Name] = reg
+	}
+
+	visited := map[string]bool{}
+	var visit func(path []string) []string
+	visit = func(path []string) []string {
+		current := path[len(path)-1]
+		for _, dep := range byName[current].DependsOn {
+			if dep == name {
+				return append(path, dep)
+			}
+			if visited[dep] {
+				continue
+			}
+			visited[dep] = true
+			if cycle := visit(append(path, dep)); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+	return visit([]string{name})
+}
+
+// withDependencies returns the registrations along with every registered
+// Registration they transitively depend on.
+func (r *Registry) withDependencies(registrations []Registration) []Registration {
+	included := map[string]bool{}
+	for _, reg := range registrations {
+		included[reg.Name] = true
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for added := true; added; {
+		added = false
+		for _, reg := range r.registrations {
+			if !included[reg.Name] {
+				continue
+			}
+			for _, dep := range reg.DependsOn {
+				if !included[dep] && r.indexOf(dep) >= 0 {
+					included[dep], added = true, true
+				}
+			}
+		}
+	}
+
+	var all []Registration
+	for _, reg := range r.registrations {
+		if included[reg.Name] {
+			all = append(all, reg)
+		}
+	}
+	for _, reg := range registrations {
+		if r.indexOf(reg.Name) < 0 {
+			// Unregistered since it was selected, but still report it
+			all = append(all, reg)
+		}
+	}
+	return all
+}
+
+// runInOrder executes the registrations in waves, each wave consisting of
+// the registrations whose dependencies have all been resolved.  Those
+// with a failing, skipped or missing dependency are skipped.
+func (r *Registry) runInOrder(ctx context.Context, registrations []Registration) map[string]checkResult {
+	names := map[string]bool{}
+	for _, reg := range registrations {
+		names[reg.Name] = true
+	}
+
+	results := map[string]checkResult{}
+	remaining := registrations
+	for len(remaining) > 0 {
+		var ready []Registration
+		var checkers []TimeoutChecker
+		var waiting []Registration
+		for _, reg := range remaining {
+			blocked, reason, resolved := dependencyState(reg, names, results)
+			switch {
+			case !resolved:
+				waiting = append(waiting, reg)
+			case blocked != "":
+				results[reg.Name] = skipped(reg, blocked, reason)
+			default:
+				ready = append(ready, reg)
+				checkers = append(checkers, reg.timeoutChecker())
+			}
+		}
+
+		if len(ready) == 0 && len(waiting) == len(remaining) {
+			// Only possible if a cycle slipped past registration
+			for _, reg := range waiting {
+				results[reg.Name] = skipped(reg, reg.DependsOn[0], "is part of a dependency cycle")
+			}
+			break
+		}
+
+		for i, res := range runEach(ctx, r.MaxConcurrency, checkers...) {
+			results[ready[i].Name] = res
+		}
+		remaining = waiting
+	}
+	return results
+}
+
+// dependencyState determines whether the registration's dependencies have
+// all been resolved and, if so, the first dependency blocking it along with
+// the reason why.
+func dependencyState(reg Registration, names map[string]bool, results map[string]checkResult) (blocked string, reason string, resolved bool) {
+	for _, dep := range reg.DependsOn {
+		if !names[dep] {
+			return dep, "isn't registered", true
+		}
+		res, ok := results[dep]
+		if !ok {
+			return "", "", false
+		}
+		switch res.status {
+		case Fail:
+			if blocked == "" {
+				blocked, reason = dep, "is failing"
+			}
+		case Undetermined:
+			if blocked == "" {
+				blocked, reason = dep, "is undetermined"
+			}
+		}
+	}
+	return blocked, reason, true
+}
+
+func skipped(reg Registration, dependency string, reason string) checkResult {
+	return checkResult{
+		details: []ComponentDetail{{
+			Key:    Key{ComponentName: reg.Name},
+			Status: Undetermined,
+			Time:   time.Now().UTC(),
+			Output: fmt.Sprintf("Skipped because dependency %q %s", dependency, reason),
+		}},
+		status: Undetermined,
+	}
+}
@@ -1,22 +1,25 @@
-// Code generated by additional-properties DO NOT EDIT.
+// The ComponentDetail codec was originally generated by additional-properties
+// but is maintained by hand, since the generated code never emitted the
+// status and time fields.
 
 package health
 
 import (
 	"encoding/json"
-	"reflect"
 	"strings"
 )
 
-type Valueea2f6e8a571941279831930cd1b66567 reflect.Value // DO NOT REMOVE (guarantees the reflect package is used)
-
-// MarshalJSON encodes the ComponentDetail struct to JSON with additional-properties
+// MarshalJSON encodes the ComponentDetail as a single JSON object with its
+// AdditionalProperties alongside the RFC's fields.  The AdditionalProperties
+// map isn't modified.
 func (c ComponentDetail) MarshalJSON() ([]byte, error) {
 	type Alias ComponentDetail
 	aux := (Alias)(c)
-	if aux.AdditionalProperties == nil {
-		aux.AdditionalProperties = map[string]interface{}{}
+	props := map[string]interface{}{}
+	for k, v := range aux.AdditionalProperties {
+		props[k] = v
 	}
+	aux.AdditionalProperties = props
 	if aux.ComponentId != "" {
 		aux.AdditionalProperties["componentId"] = aux.ComponentId
 	}
@@ -29,13 +32,11 @@ func (c ComponentDetail) MarshalJSON() ([]byte, error) {
 	if aux.ObservedUnit != "" {
 		aux.AdditionalProperties["observedUnit"] = aux.ObservedUnit
 	}
-	if reflect.ValueOf(aux.Status) == reflect.Zero(reflect.TypeOf(aux.Status)) {
-		aux.AdditionalProperties["status"] = aux.Status
-	}
+	aux.AdditionalProperties["status"] = aux.Status
 	if len(aux.AffectedEndpoints) != 0 {
 		aux.AdditionalProperties["affectedEndpoints"] = aux.AffectedEndpoints
 	}
-	if reflect.ValueOf(aux.Time) == reflect.Zero(reflect.TypeOf(aux.Time)) {
+	if !aux.Time.IsZero() {
 		aux.AdditionalProperties["time"] = aux.Time
 	}
 	if aux.Output != "" {
@@ -47,7 +48,8 @@ func (c ComponentDetail) MarshalJSON() ([]byte, error) {
 	return json.Marshal(aux.AdditionalProperties)
 }
 
-// UnmarshalJSON decodes JSON into the ComponentDetail struct with additional-properties
+// UnmarshalJSON decodes a JSON object into the ComponentDetail, collecting
+// any fields the RFC doesn't define into AdditionalProperties.
 func (c *ComponentDetail) UnmarshalJSON(data []byte) error {
 	type Alias ComponentDetail
 	aux := (*Alias)(c)
@@ -61,11 +63,11 @@ func (c *ComponentDetail) UnmarshalJSON(data []byte) error {
 		"componentType": true, "componenttype": true,
 		"observedValue": true, "observedvalue": true,
 		"observedUnit": true, "observedunit": true,
-		"status": true,
+		"status":            true,
 		"affectedEndpoints": true, "affectedendpoints": true,
-		"time": true,
+		"time":   true,
 		"output": true,
-		"links": true,
+		"links":  true,
 	}
 	for k := range c.AdditionalProperties {
 		if names[k] {
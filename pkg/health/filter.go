@@ -0,0 +1,174 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Filter selects the ComponentDetail objects included in a Health
+// document.  A ComponentDetail is selected if it matches any of the
+// Components or Keys (or if neither is provided) and any of the Statuses
+// (or if none are provided).  Statuses are matched as they're reported to
+// clients, so Undetermined matches Fail.
+//
+// Registries only execute the Registrations whose Name matches one of the
+// Components or Keys' ComponentNames, so Checkers should be registered
+// under the ComponentName they report when they're to be filtered.
+type Filter struct {
+	Components []string
+	Keys       []Key
+	Statuses   []Status
+	// Terse reports only the aggregate Status.
+	Terse bool
+}
+
+type filterContextKey struct{}
+
+// ParseFilter creates a Filter from the "component", "key", "status" and
+// "verbose" query parameters.  Each parameter can be repeated or contain a
+// comma-separated list of values (e.g. "?status=warn,fail").
+func ParseFilter(query url.Values) (Filter, error) {
+	var f Filter
+	f.Components = queryValues(query, "component")
+	for _, v := range queryValues(query, "key") {
+		var k Key
+		if err := k.UnmarshalText([]byte(v)); err != nil {
+			return f, fmt.Errorf("Couldn't parse Key with value: %v", v)
+		}
+		f.Keys = append(f.Keys, k)
+	}
+	for _, v := range queryValues(query, "status") {
+		s, err := ParseStatus(v)
+		if err != nil {
+			return f, err
+		}
+		f.Statuses = append(f.Statuses, s.rfc())
+	}
+	if v := query.Get("verbose"); v != "" {
+		verbose, err := strconv.ParseBool(v)
+		if err != nil {
+			return f, fmt.Errorf("Couldn't parse verbose with value: %v", v)
+		}
+		f.Terse = !verbose
+	}
+	return f, nil
+}
+
+func queryValues(query url.Values, name string) []string {
+	var values []string
+	for _, param := range query[name] {
+		for _, v := range strings.Split(param, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// WithFilter returns a copy of ctx that causes the Handler and Registry to
+// select only the results matching the Filter.
+func WithFilter(ctx context.Context, f Filter) context.Context {
+	return context.WithValue(ctx, filterContextKey{}, f)
+}
+
+// filterFromContext returns the Filter carried by ctx, and whether it
+// selects anything less than every result.
+func filterFromContext(ctx context.Context) (Filter, bool) {
+	f, ok := ctx.Value(filterContextKey{}).(Filter)
+	return f, ok && !f.empty()
+}
+
+// empty returns true if the Filter selects every result.
+func (f Filter) empty() bool {
+	return !f.selectsKeys() && len(f.Statuses) == 0
+}
+
+// Matches returns true if the Filter selects the ComponentDetail.
+func (f Filter) Matches(detail ComponentDetail) bool {
+	return f.matchesKey(detail.Key) && f.matchesStatus(detail.Status)
+}
+
+func (f Filter) selectsKeys() bool {
+	return len(f.Components) != 0 || len(f.Keys) != 0
+}
+
+func (f Filter) matchesKey(key Key) bool {
+	if !f.selectsKeys() {
+		return true
+	}
+	for _, c := range f.Components {
+		if key.ComponentName == c {
+			return true
+		}
+	}
+	for _, k := range f.Keys {
+		if key == k {
+			return true
+		}
+	}
+	return false
+}
+
+func (f Filter) matchesStatus(status Status) bool {
+	if len(f.Statuses) == 0 {
+		return true
+	}
+	for _, s := range f.Statuses {
+		if status.rfc() == s.rfc() {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesName returns true if a Registration with the provided Name could
+// report selected results.
+func (f Filter) matchesName(name string) bool {
+	if !f.selectsKeys() {
+		return true
+	}
+	for _, c := range f.Components {
+		if name == c {
+			return true
+		}
+	}
+	for _, k := range f.Keys {
+		if name == k.ComponentName {
+			return true
+		}
+	}
+	return false
+}
+
+// apply selects the matching details from a single Checker's results.  If
+// any details are removed, the Status is recomputed from the remaining
+// details without exceeding the Checker's own Status.
+func (f Filter) apply(details []ComponentDetail, status Status) ([]ComponentDetail, Status) {
+	var selected []ComponentDetail
+	selectedStatus := Pass
+	for _, detail := range details {
+		if f.Matches(detail) {
+			selected = append(selected, detail)
+			selectedStatus = selectedStatus.Max(detail.Status)
+		}
+	}
+	if len(selected) == len(details) {
+		return details, status
+	}
+	if selectedStatus.Severity() > status.Severity() {
+		selectedStatus = status
+	}
+	return selected, selectedStatus
+}
+
+// withoutFilter returns a copy of ctx that doesn't carry a Filter.
+func withoutFilter(ctx context.Context) context.Context {
+	if _, ok := filterFromContext(ctx); !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, filterContextKey{}, nil)
+}
@@ -0,0 +1,146 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	assert := assert.New(t)
+	query, _ := url.ParseQuery("component=cassandra&component=cpu,memory&key=cpu:utilization&status=warn,fail&verbose=false")
+
+	f, err := ParseFilter(query)
+
+	require.NoError(t, err)
+	assert.Equal([]string{"cassandra", "cpu", "memory"}, f.Components)
+	assert.Equal([]Key{{ComponentName: "cpu", MeasurementName: "utilization"}}, f.Keys)
+	assert.Equal([]Status{Warn, Fail}, f.Statuses)
+	assert.True(f.Terse)
+}
+
+func TestParseFilterTreatsUndeterminedAsFail(t *testing.T) {
+	query, _ := url.ParseQuery("status=undetermined,unknown")
+
+	f, err := ParseFilter(query)
+
+	require.NoError(t, err)
+	assert.Equal(t, []Status{Fail, Fail}, f.Statuses)
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	for _, q := range []string{"status=sideways", "verbose=maybe"} {
+		query, _ := url.ParseQuery(q)
+		_, err := ParseFilter(query)
+		assert.Error(t, err, q)
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	assert := assert.New(t)
+	cpu := ComponentDetail{Key: Key{ComponentName: "cpu", MeasurementName: "utilization"}, Status: Warn}
+	db := ComponentDetail{Key: Key{ComponentName: "db", MeasurementName: "connections"}, Status: Pass}
+
+	assert.True(Filter{}.Matches(cpu))
+	assert.True(Filter{Components: []string{"cpu"}}.Matches(cpu))
+	assert.False(Filter{Components: []string{"cpu"}}.Matches(db))
+	assert.True(Filter{Components: []string{"cpu"}, Keys: []Key{db.Key}}.Matches(db))
+	assert.True(Filter{Statuses: []Status{Warn, Fail}}.Matches(cpu))
+	assert.False(Filter{Statuses: []Status{Warn, Fail}}.Matches(db))
+	assert.False(Filter{Components: []string{"cpu"}, Statuses: []Status{Fail}}.Matches(cpu))
+
+	skipped := ComponentDetail{Key: Key{ComponentName: "query"}, Status: Undetermined}
+	assert.True(Filter{Statuses: []Status{Fail}}.Matches(skipped))
+	assert.False(Filter{Statuses: []Status{Warn}}.Matches(skipped))
+}
+
+func TestFilterApplyCapsAtCheckerStatus(t *testing.T) {
+	details := []ComponentDetail{
+		{Key: Key{ComponentName: "a"}, Status: Fail},
+		{Key: Key{ComponentName: "b"}, Status: Pass},
+	}
+
+	selected, status := Filter{Components: []string{"a"}}.apply(details, Warn)
+
+	assert.Len(t, selected, 1)
+	assert.Equal(t, Warn, status)
+}
+
+func serveFiltered(t *testing.T, handler http.Handler, query string) (int, Health) {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health?"+query, nil))
+	var h Health
+	if rec.Code != http.StatusBadRequest {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &h))
+	}
+	return rec.Code, h
+}
+
+func TestHandlerFiltersChecks(t *testing.T) {
+	assert := assert.New(t)
+	var r Registry
+	var dbRuns int32
+	require.NoError(t, r.Register(Registration{Name: "cpu", Checker: detailChecker("cpu", Warn)}))
+	require.NoError(t, r.Register(Registration{Name: "db", Checker: runCounter{key: Key{ComponentName: "db"}, runs: &dbRuns}}))
+	require.NoError(t, r.Register(Registration{Name: "cassandra", Checker: detailChecker("cassandra", Fail)}))
+	handler := NewHandler(Service{Version: "1"}, &r)
+
+	code, h := serveFiltered(t, handler, "component=cpu")
+	assert.Equal(http.StatusOK, code)
+	assert.Equal(Warn, h.Status)
+	assert.Len(h.Checks, 1)
+	assert.Contains(h.Checks, Key{ComponentName: "cpu"})
+	assert.Equal(int32(0), atomic.LoadInt32(&dbRuns))
+
+	code, h = serveFiltered(t, handler, "key=db")
+	assert.Equal(http.StatusOK, code)
+	assert.Equal(Pass, h.Status)
+	assert.Contains(h.Checks, Key{ComponentName: "db"})
+	assert.Equal(int32(1), atomic.LoadInt32(&dbRuns))
+
+	code, h = serveFiltered(t, handler, "status=warn,fail")
+	assert.Equal(http.StatusServiceUnavailable, code)
+	assert.Len(h.Checks, 2)
+	assert.NotContains(h.Checks, Key{ComponentName: "db"})
+}
+
+func TestHandlerFiltersSkippedDependentsAsFail(t *testing.T) {
+	assert := assert.New(t)
+	var r Registry
+	require.NoError(t, r.Register(Registration{Name: "pg", Checker: detailChecker("pg", Fail)}))
+	require.NoError(t, r.Register(Registration{Name: "query", Checker: detailChecker("query", Pass), DependsOn: []string{"pg"}}))
+	require.NoError(t, r.Register(Registration{Name: "cpu", Checker: detailChecker("cpu", Warn)}))
+	handler := NewHandler(Service{}, &r)
+
+	for _, query := range []string{"status=fail", "status=undetermined"} {
+		code, h := serveFiltered(t, handler, query)
+		assert.Equal(http.StatusServiceUnavailable, code, query)
+		assert.Equal(Fail, h.Status, query)
+		assert.Len(h.Checks, 2, query)
+		assert.Contains(h.Checks, Key{ComponentName: "pg"}, query)
+		require.Contains(t, h.Checks, Key{ComponentName: "query"}, query)
+		assert.Equal(Fail, h.Checks[Key{ComponentName: "query"}][0].Status, query)
+	}
+}
+
+func TestHandlerTerse(t *testing.T) {
+	handler := NewHandler(Service{Version: "1"}, detailChecker("db", Fail))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health?verbose=false", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.JSONEq(t, `{"status":"fail"}`, rec.Body.String())
+}
+
+func TestHandlerRejectsInvalidFilter(t *testing.T) {
+	code, _ := serveFiltered(t, NewHandler(Service{}, detailChecker("db", Pass)), "status=sideways")
+
+	assert.Equal(t, http.StatusBadRequest, code)
+}
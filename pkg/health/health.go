@@ -1,5 +1,3 @@
-//go:generate additional-properties
-
 package health
 
 import (
@@ -39,36 +37,63 @@ type Status int
 //These must be organized from least to greatest severity and must be in
 //the same order as the data structure below. (Replace with go-enumeration
 //when it's available.)
+//
+//Undetermined isn't defined by the RFC.  It indicates that a check hasn't
+//run yet, or that its outcome couldn't be determined, and is considered
+//more severe than Warn (since the service isn't known to be healthy) but
+//less severe than Fail.  So that documents remain valid for consumers that
+//only accept the RFC's statuses, Undetermined is marshaled, and matched by
+//Filters, as "fail".  It is only distinguished from Fail within the
+//process.
 const (
-	Pass Status = iota
-	Warn Status = 1
-	Fail Status = 2
+	Pass         Status = iota
+	Warn         Status = 1
+	Undetermined Status = 2
+	Fail         Status = 3
 )
 
 var statusData = []struct {
 	name         string
 	responseCode int
+	aliases      []string
 }{
-	{"Pass", http.StatusOK},
-	{"Warn", http.StatusOK},
-	{"Fail", http.StatusServiceUnavailable},
-	//{"Undetermined", http.StatusOK},
+	{"Pass", http.StatusOK, []string{"ok", "up"}},
+	{"Warn", http.StatusOK, nil},
+	{"Undetermined", http.StatusServiceUnavailable, []string{"unknown"}},
+	{"Fail", http.StatusServiceUnavailable, []string{"error", "down"}},
 }
 
+// ParseStatus parses the case-insensitive name of a Status, along with the
+// aliases the RFC asks consumers to accept ("ok" and "up" for Pass, "error"
+// and "down" for Fail) and "unknown" for Undetermined.
 func ParseStatus(input string) (Status, error) {
-	switch strings.ToLower(input) {
-	case strings.ToLower(Pass.String()):
-		return Pass, nil
-	case strings.ToLower(Fail.String()):
-		return Fail, nil
-	case strings.ToLower(Warn.String()):
-		return Warn, nil
+	lower := strings.ToLower(input)
+	for i, data := range statusData {
+		if lower == strings.ToLower(data.name) {
+			return Status(i), nil
+		}
+		for _, alias := range data.aliases {
+			if lower == alias {
+				return Status(i), nil
+			}
+		}
 	}
 	return Pass, fmt.Errorf("Couldn't parse Status with value: %v", input)
 }
 
+// MarshalText encodes the Status using the RFC's names, so Undetermined is
+// encoded as "fail".
 func (s Status) MarshalText() ([]byte, error) {
-	return []byte(strings.ToLower(s.String())), nil
+	return []byte(strings.ToLower(s.rfc().String())), nil
+}
+
+// rfc returns the Status reported to clients, which is Fail for
+// Undetermined.
+func (s Status) rfc() Status {
+	if s == Undetermined {
+		return Fail
+	}
+	return s
 }
 
 func (s Status) Max(other Status) Status {
@@ -83,13 +108,23 @@ func (s Status) Severity() int {
 }
 
 func (s Status) String() string {
+	if !s.valid() {
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
 	return statusData[s].name
 }
 
 func (s Status) StatusCode() int {
+	if !s.valid() {
+		return http.StatusInternalServerError
+	}
 	return statusData[s].responseCode
 }
 
+func (s Status) valid() bool {
+	return s >= 0 && int(s) < len(statusData)
+}
+
 func (s *Status) UnmarshalText(json []byte) error {
 	st, err := ParseStatus(string(json))
 	if err == nil {
@@ -131,12 +166,30 @@ func (c Checks) Merge(checks ...Checks) {
 
 //Key provides a composite key denoting the component name and measurement
 //name of a health checks.
+//
+//Keys are encoded as "componentName:measurementName" (or just
+//"componentName" when there's no measurement name).  So that component
+//names containing colons (e.g. URLs) survive a round-trip, any "%" or ":"
+//in the component name is percent-encoded as "%25" or "%3A" respectively.
+//When decoding, the first ":" therefore always separates the component
+//name from the measurement name, which is used verbatim and may itself
+//contain colons.  Other percent signs in the component name are left
+//untouched.
 type Key struct {
 	ComponentName   string
 	MeasurementName string
 }
 
+var (
+	componentNameEscaper   = strings.NewReplacer("%", "%25", ":", "%3A")
+	componentNameUnescaper = strings.NewReplacer("%25", "%", "%3A", ":", "%3a", ":")
+)
+
+// MarshalText encodes the Key, returning an error if the Key is invalid.
 func (k Key) MarshalText() ([]byte, error) {
+	if v := k.Validate(); len(v) != 0 {
+		return nil, v
+	}
 	return []byte(k.String()), nil
 }
 
@@ -154,7 +207,7 @@ func (k *Key) Scan(state fmt.ScanState, verb rune) error {
 	if err != nil {
 		return err
 	}
-	k.ComponentName = string(cn)
+	k.ComponentName = componentNameUnescaper.Replace(string(cn))
 
 	_, _, err = state.ReadRune()
 	if err != nil && (err == io.ErrUnexpectedEOF || err == io.EOF) {
@@ -176,11 +229,13 @@ func (k *Key) Scan(state fmt.ScanState, verb rune) error {
 	return nil
 }
 
+// String returns the encoded form of the Key.
 func (k Key) String() string {
+	componentName := componentNameEscaper.Replace(k.ComponentName)
 	if k.MeasurementName == "" {
-		return k.ComponentName
+		return componentName
 	}
-	return k.ComponentName + ":" + k.MeasurementName
+	return componentName + ":" + k.MeasurementName
 }
 
 func (k *Key) UnmarshalText(text []byte) error {
@@ -207,3 +262,15 @@ type ComponentDetail struct {
 	Links                map[string]string      `json:"links,omitempty"`
 	AdditionalProperties map[string]interface{} `json:"*,omitempty"`
 }
+
+// withProperty returns a copy of the ComponentDetail with the additional
+// property set, leaving the receiver's AdditionalProperties unchanged.
+func (c ComponentDetail) withProperty(name string, value interface{}) ComponentDetail {
+	props := make(map[string]interface{}, len(c.AdditionalProperties)+1)
+	for k, v := range c.AdditionalProperties {
+		props[k] = v
+	}
+	props[name] = value
+	c.AdditionalProperties = props
+	return c
+}
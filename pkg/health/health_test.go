@@ -3,10 +3,12 @@ package health
 import (
 	"encoding/json"
 	"io"
+	"net/http"
 	"testing"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMarshaling(t *testing.T) {
@@ -40,9 +42,8 @@ func TestMarshaling(t *testing.T) {
 func TestKeyMarshalTextWithEmptyComponentNameAndMeasurementName(t *testing.T) {
 	var key Key
 	bytes, err := key.MarshalText()
-	log.Info("Bytes: ", bytes)
-	log.Info("Err: ", err)
-	//TODO: this should cause an error
+	assert.Error(t, err)
+	assert.Nil(t, bytes)
 }
 
 func TestKeyMarshalTextWithEmptyComponentName(t *testing.T) {
@@ -51,8 +52,8 @@ func TestKeyMarshalTextWithEmptyComponentName(t *testing.T) {
 		MeasurementName: "Not Empty",
 	}
 	bytes, err := key.MarshalText()
-	assert.NoError(t, err)
-	assert.Equal(t, []byte(":Not Empty"), bytes)
+	assert.Error(t, err)
+	assert.Nil(t, bytes)
 }
 
 func TestKeyMarshalTextWithEmptyMeasurementName(t *testing.T) {
@@ -98,3 +99,109 @@ func TestKeyUnmarshalTextWithTwoPartKey(t *testing.T) {
 	assert.Equal("testComponent", k.ComponentName)
 	assert.Equal("testMeasurement", k.MeasurementName)
 }
+
+func TestCriticalityApply(t *testing.T) {
+	assert := assert.New(t)
+	for _, s := range []Status{Pass, Warn, Fail} {
+		assert.Equal(s, Critical.Apply(s))
+		assert.Equal(Pass, Informational.Apply(s))
+	}
+	assert.Equal(Pass, NonCritical.Apply(Pass))
+	assert.Equal(Warn, NonCritical.Apply(Warn))
+	assert.Equal(Warn, NonCritical.Apply(Fail))
+}
+
+func TestKeyRoundTrip(t *testing.T) {
+	keys := []Key{
+		{ComponentName: "cassandra", MeasurementName: "responseTime"},
+		{ComponentName: "uptime"},
+		{ComponentName: "https://host:8443/x", MeasurementName: "HTTP/1.1 Status"},
+		{ComponentName: "https://host:8443/x"},
+		{ComponentName: "billing/https://host:8443/x", MeasurementName: "status"},
+		{ComponentName: "db", MeasurementName: "replica:lag"},
+		{ComponentName: "disk%usage"},
+		{ComponentName: "literal%3A", MeasurementName: "escaped"},
+		{ComponentName: "health.slowChecker"},
+	}
+	for _, key := range keys {
+		text, err := key.MarshalText()
+		assert.NoError(t, err, key)
+
+		var decoded Key
+		err = decoded.UnmarshalText(text)
+		assert.NoError(t, err, string(text))
+		assert.Equal(t, key, decoded, string(text))
+	}
+}
+
+func TestKeyEncoding(t *testing.T) {
+	key := Key{ComponentName: "https://host:8443/x", MeasurementName: "Latency"}
+
+	assert.Equal(t, "https%3A//host%3A8443/x:Latency", key.String())
+}
+
+func TestKeyUnmarshalTextLeavesUnknownEscapes(t *testing.T) {
+	var k Key
+	err := k.UnmarshalText([]byte("disk%20usage:percent"))
+	assert.NoError(t, err)
+	assert.Equal(t, "disk%20usage", k.ComponentName)
+	assert.Equal(t, "percent", k.MeasurementName)
+}
+
+func TestChecksRoundTripWithColons(t *testing.T) {
+	require := require.New(t)
+	key := Key{ComponentName: "https://host:8443/x", MeasurementName: "HTTP/1.1 Status"}
+	checks := Checks{}
+	checks.Add(key, ComponentDetail{Key: key, Status: Fail})
+
+	data, err := json.Marshal(checks)
+	require.NoError(err)
+	var decoded Checks
+	require.NoError(json.Unmarshal(data, &decoded))
+
+	require.Len(decoded[key], 1)
+	assert.Equal(t, Fail, decoded[key][0].Status)
+}
+
+func TestParseStatus(t *testing.T) {
+	tests := map[string]Status{
+		"pass":         Pass,
+		"PASS":         Pass,
+		"ok":           Pass,
+		"UP":           Pass,
+		"warn":         Warn,
+		"undetermined": Undetermined,
+		"unknown":      Undetermined,
+		"fail":         Fail,
+		"error":        Fail,
+		"Down":         Fail,
+	}
+	for input, expected := range tests {
+		s, err := ParseStatus(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, s, input)
+	}
+
+	_, err := ParseStatus("sideways")
+	assert.Error(t, err)
+}
+
+func TestStatusAliasesCanBeUnmarshaled(t *testing.T) {
+	var h Health
+	err := json.Unmarshal([]byte(`{"status":"up","checks":{"db":[{"status":"down"}]}}`), &h)
+
+	assert.NoError(t, err)
+	assert.Equal(t, Pass, h.Status)
+	assert.Equal(t, Fail, h.Checks[Key{ComponentName: "db"}][0].Status)
+}
+
+func TestUndeterminedSeverity(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(Undetermined, Warn.Max(Undetermined))
+	assert.Equal(Fail, Undetermined.Max(Fail))
+	assert.Equal(http.StatusServiceUnavailable, Undetermined.StatusCode())
+
+	text, err := Undetermined.MarshalText()
+	assert.NoError(err)
+	assert.Equal("fail", string(text))
+}
@@ -0,0 +1,71 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// maxStackLines limits the portion of a panic's stack trace that is
+// reported in a ComponentDetail's Output.
+const maxStackLines = 20
+
+// aggregator is implemented by the Checkers in this package that combine
+// other Checkers, and for which reporting no results is legitimate.
+type aggregator interface {
+	aggregates() bool
+}
+
+// isolatedCheck executes the checker, converting a panic or an empty
+// result into a single Fail ComponentDetail reported under key.
+func isolatedCheck(ctx context.Context, key Key, checker ContextChecker) (details []ComponentDetail, status Status) {
+	start := time.Now().UTC()
+	defer func() {
+		if r := recover(); r != nil {
+			details, status = failed(key, start, fmt.Sprintf("Check panicked: %v\n%s", r, panicStack())), Fail
+		}
+	}()
+
+	details, status = checker.CheckContext(ctx)
+	if len(details) == 0 {
+		if aggregates(checker) {
+			return details, status
+		}
+		return failed(key, start, "Check returned no results"), Fail
+	}
+	return details, status
+}
+
+func aggregates(checker Checker) bool {
+	a, ok := checker.(aggregator)
+	return ok && a.aggregates()
+}
+
+func failed(key Key, start time.Time, output string) []ComponentDetail {
+	return []ComponentDetail{{
+		Key:    key,
+		Status: Fail,
+		Time:   start,
+		Output: output,
+	}}
+}
+
+// panicStack returns the stack of the panicking goroutine starting at the
+// frame that panicked.
+func panicStack() string {
+	lines := strings.Split(strings.TrimSpace(string(debug.Stack())), "\n")
+	// Skip the goroutine header and the frames of debug.Stack, this
+	// package's recovery and the runtime's panic handling.
+	for i, line := range lines {
+		if strings.HasPrefix(line, "panic(") {
+			lines = lines[i+2:]
+			break
+		}
+	}
+	if len(lines) > maxStackLines {
+		lines = lines[:maxStackLines]
+	}
+	return strings.Join(lines, "\n")
+}
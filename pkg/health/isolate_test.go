@@ -0,0 +1,67 @@
+package health
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type panickingChecker struct{}
+
+func (p panickingChecker) Check() ([]ComponentDetail, Status) {
+	panic("boom")
+}
+
+func TestPanicIsReportedAsFailure(t *testing.T) {
+	assert := assert.New(t)
+	key := Key{ComponentName: "panicky"}
+
+	details, status := TimeoutChecker{Key: key, Checker: panickingChecker{}}.Check()
+
+	assert.Equal(Fail, status)
+	require.Len(t, details, 1)
+	assert.Equal(key, details[0].Key)
+	assert.Equal(Fail, details[0].Status)
+	assert.Contains(details[0].Output, "Check panicked: boom")
+	assert.Contains(details[0].Output, "panickingChecker.Check")
+	assert.NotContains(details[0].Output, "runtime/debug.Stack")
+}
+
+func TestEmptyResultIsReportedAsFailure(t *testing.T) {
+	key := Key{ComponentName: "empty"}
+
+	details, status := TimeoutChecker{Key: key, Checker: testChecker{}}.Check()
+
+	assert.Equal(t, Fail, status)
+	require.Len(t, details, 1)
+	assert.Equal(t, key, details[0].Key)
+	assert.Contains(t, details[0].Output, "no results")
+}
+
+func TestEmptyAggregateIsNotAFailure(t *testing.T) {
+	var r Registry
+
+	details, status := TimeoutChecker{Checker: &r}.Check()
+
+	assert.Equal(t, Pass, status)
+	assert.Empty(t, details)
+}
+
+func TestHandlerServesReportDespitePanic(t *testing.T) {
+	assert := assert.New(t)
+	var r Registry
+	require.NoError(t, r.Register(Registration{Name: "panicky", Checker: panickingChecker{}}))
+	require.NoError(t, r.Register(Registration{Name: "empty", Checker: testChecker{}}))
+	require.NoError(t, r.Register(Registration{Name: "db", Checker: detailChecker("db", Pass)}))
+
+	rec := httptest.NewRecorder()
+	NewHandler(Service{}, &r).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	assert.Equal(http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(rec.Body.String(), `"panicky"`)
+	assert.Contains(rec.Body.String(), `"empty"`)
+	assert.Contains(rec.Body.String(), `"db"`)
+}
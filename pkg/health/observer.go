@@ -0,0 +1,161 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Transition describes a change in the Status of a Key, or in the
+// aggregate Status when Key is the zero value.
+type Transition struct {
+	Key  Key
+	From Status
+	To   Status
+	// Detail is the most severe ComponentDetail reported for the Key when
+	// the transition was observed.  It is the zero value for aggregate
+	// transitions.
+	Detail ComponentDetail
+	Time   time.Time
+}
+
+// Aggregate returns true if the Transition describes a change in the
+// aggregate Status rather than the Status of a single Key.
+func (t Transition) Aggregate() bool {
+	return t.Key == Key{}
+}
+
+// Observer executes its Checker, reports the results unchanged and
+// publishes a Transition to its subscribers each time the Status of a
+// Key, or the aggregate Status, changes.  Keys and the aggregate are
+// initially considered to be passing, so a Key first reported as Warn
+// produces a transition from Pass to Warn.  Results restricted by a
+// Filter only update the Keys they include.
+//
+// An Observer fires whenever it is executed, so it can be passed to a
+// Scheduler, to a Handler or to both.  It must be used through a pointer
+// and should not be copied after first use.
+type Observer struct {
+	Checker Checker
+
+	mu        sync.Mutex
+	statuses  map[Key]Status
+	aggregate Status
+	next      int
+	callbacks map[int]func(Transition)
+}
+
+// Check executes the Checker with a background context.
+func (o *Observer) Check() ([]ComponentDetail, Status) {
+	return o.CheckContext(context.Background())
+}
+
+// CheckContext executes the Checker and publishes any resulting
+// Transitions before returning its results.
+func (o *Observer) CheckContext(ctx context.Context) ([]ComponentDetail, Status) {
+	details, status := WithContext(o.Checker).CheckContext(ctx)
+	_, filtered := filterFromContext(ctx)
+	o.observe(details, status, !filtered, time.Now().UTC())
+	return details, status
+}
+
+// Subscribe registers a callback that is invoked, on the goroutine that
+// executed the Observer, with each Transition.  The returned function
+// cancels the subscription.  Callbacks must not subscribe to, or
+// unsubscribe from, the Observer.
+func (o *Observer) Subscribe(callback func(Transition)) (unsubscribe func()) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.callbacks == nil {
+		o.callbacks = map[int]func(Transition){}
+	}
+	id := o.next
+	o.next++
+	o.callbacks[id] = callback
+
+	return func() {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		delete(o.callbacks, id)
+	}
+}
+
+// Watch returns a channel that receives each Transition.  Transitions are
+// dropped, rather than delaying the Observer, when the channel's buffer is
+// full.  The returned function cancels the subscription and closes the
+// channel.
+func (o *Observer) Watch(buffer int) (<-chan Transition, func()) {
+	ch := make(chan Transition, buffer)
+	unsubscribe := o.Subscribe(func(t Transition) {
+		select {
+		case ch <- t:
+		default:
+			log.WithField("Key", t.Key).WithField("Status", t.To).Warn("Dropped health transition for slow watcher")
+		}
+	})
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			unsubscribe()
+			close(ch)
+		})
+	}
+}
+
+// observe records the observed Statuses and publishes the Transitions.
+// The aggregate Status is only recorded when complete is true (i.e. the
+// results weren't filtered).  The Observer's lock is held while publishing
+// so that subscribers receive Transitions in order and never after
+// unsubscribing.
+func (o *Observer) observe(details []ComponentDetail, status Status, complete bool, now time.Time) {
+	var keys []Key
+	current := map[Key]ComponentDetail{}
+	for _, detail := range details {
+		prev, ok := current[detail.Key]
+		if !ok {
+			keys = append(keys, detail.Key)
+		}
+		if !ok || detail.Status.Severity() > prev.Status.Severity() {
+			current[detail.Key] = detail
+		}
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var transitions []Transition
+	for _, key := range keys {
+		detail := current[key]
+		from, ok := o.statuses[key]
+		if !ok {
+			from = Pass
+		}
+		if from != detail.Status {
+			transitions = append(transitions, Transition{Key: key, From: from, To: detail.Status, Detail: detail, Time: now})
+		}
+	}
+	if complete && o.aggregate != status {
+		transitions = append(transitions, Transition{From: o.aggregate, To: status, Time: now})
+		o.aggregate = status
+	}
+
+	if o.statuses == nil {
+		o.statuses = map[Key]Status{}
+	}
+	for key, detail := range current {
+		o.statuses[key] = detail.Status
+	}
+
+	for _, t := range transitions {
+		for _, callback := range o.callbacks {
+			callback(t)
+		}
+	}
+}
+
+func (o *Observer) aggregates() bool {
+	return aggregates(o.Checker)
+}
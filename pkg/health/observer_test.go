@@ -0,0 +1,124 @@
+package health
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserverPublishesTransitions(t *testing.T) {
+	assert := assert.New(t)
+	o := &Observer{Checker: sequenceChecker{statuses: []Status{Pass, Fail, Fail, Pass}, next: new(int)}}
+	var transitions []Transition
+	o.Subscribe(func(t Transition) {
+		transitions = append(transitions, t)
+	})
+
+	o.Check()
+	assert.Empty(transitions)
+
+	_, status := o.Check()
+	assert.Equal(Fail, status)
+	require.Len(t, transitions, 2)
+	assert.Equal(Key{ComponentName: "db"}, transitions[0].Key)
+	assert.Equal(Pass, transitions[0].From)
+	assert.Equal(Fail, transitions[0].To)
+	assert.Equal(Fail, transitions[0].Detail.Status)
+	assert.False(transitions[0].Time.IsZero())
+	assert.False(transitions[0].Aggregate())
+	assert.True(transitions[1].Aggregate())
+	assert.Equal(Fail, transitions[1].To)
+
+	o.Check()
+	assert.Len(transitions, 2)
+
+	o.Check()
+	require.Len(t, transitions, 4)
+	assert.Equal(Fail, transitions[2].From)
+	assert.Equal(Pass, transitions[2].To)
+}
+
+func TestObserverUsesMostSevereDetail(t *testing.T) {
+	key := Key{ComponentName: "cpu", MeasurementName: "utilization"}
+	o := &Observer{Checker: testChecker{
+		details: []ComponentDetail{
+			{Key: key, Status: Pass},
+			{Key: key, Status: Warn, Output: "node 2"},
+		},
+		status: Warn,
+	}}
+	ch, cancel := o.Watch(10)
+	defer cancel()
+
+	o.Check()
+
+	transition := <-ch
+	assert.Equal(t, key, transition.Key)
+	assert.Equal(t, Warn, transition.To)
+	assert.Equal(t, "node 2", transition.Detail.Output)
+	assert.True(t, (<-ch).Aggregate())
+}
+
+func TestObserverUnsubscribe(t *testing.T) {
+	o := &Observer{Checker: sequenceChecker{statuses: []Status{Fail, Pass}, next: new(int)}}
+	ch, cancel := o.Watch(10)
+	calls := 0
+	unsubscribe := o.Subscribe(func(Transition) { calls++ })
+
+	unsubscribe()
+	cancel()
+	cancel()
+	o.Check()
+
+	assert.Equal(t, 0, calls)
+	_, open := <-ch
+	assert.False(t, open)
+}
+
+func TestObserverDropsForSlowWatchers(t *testing.T) {
+	o := &Observer{Checker: sequenceChecker{statuses: []Status{Fail}, next: new(int)}}
+	ch, cancel := o.Watch(1)
+	defer cancel()
+
+	o.Check()
+
+	assert.Len(t, ch, 1)
+}
+
+func TestObserverIgnoresFilteredAggregate(t *testing.T) {
+	var r Registry
+	require.NoError(t, r.Register(Registration{Name: "db", Checker: detailChecker("db", Fail)}))
+	require.NoError(t, r.Register(Registration{Name: "cpu", Checker: detailChecker("cpu", Pass)}))
+	o := &Observer{Checker: &r}
+	var transitions []Transition
+	o.Subscribe(func(t Transition) {
+		transitions = append(transitions, t)
+	})
+
+	o.Check()
+	require.Len(t, transitions, 2)
+
+	o.CheckContext(WithFilter(context.Background(), Filter{Components: []string{"cpu"}}))
+	o.Check()
+	assert.Len(t, transitions, 2)
+}
+
+func TestObserverPublishesAggregateBehindHandler(t *testing.T) {
+	o := &Observer{Checker: sequenceChecker{statuses: []Status{Pass, Fail}, next: new(int)}}
+	var aggregates []Transition
+	o.Subscribe(func(t Transition) {
+		if t.Aggregate() {
+			aggregates = append(aggregates, t)
+		}
+	})
+	handler := NewHandler(Service{}, o)
+
+	serveCode(handler)
+	serveCode(handler)
+
+	require.Len(t, aggregates, 1)
+	assert.Equal(t, Pass, aggregates[0].From)
+	assert.Equal(t, Fail, aggregates[0].To)
+}
@@ -0,0 +1,77 @@
+package health
+
+import (
+	"context"
+	"sync"
+)
+
+// Tags that assign registered Checkers to the Kubernetes liveness,
+// readiness and startup probes.  A Checker may carry more than one.
+//
+// See - https://kubernetes.io/docs/tasks/configure-pod-container/configure-liveness-readiness-startup-probes/
+const (
+	LivenessTag  = "liveness"
+	ReadinessTag = "readiness"
+	StartupTag   = "startup"
+)
+
+// NewLivenessHandler returns a Handler (e.g. for /livez) that executes the
+// registered Checkers tagged with LivenessTag.
+func NewLivenessHandler(service Service, registry *Registry) *Handler {
+	return NewHandler(service, registry.Tagged(LivenessTag))
+}
+
+// NewReadinessHandler returns a Handler (e.g. for /readyz) that executes the
+// registered Checkers tagged with ReadinessTag.
+func NewReadinessHandler(service Service, registry *Registry) *Handler {
+	return NewHandler(service, registry.Tagged(ReadinessTag))
+}
+
+// NewStartupHandler returns a Handler (e.g. for /startupz) that executes the
+// registered Checkers tagged with StartupTag until they have all passed,
+// after which it always reports Pass.
+func NewStartupHandler(service Service, registry *Registry) *Handler {
+	return NewHandler(service, Latch(registry.Tagged(StartupTag)))
+}
+
+// Latch returns a Checker that executes the provided checker until it
+// reports Pass along with at least one ComponentDetail.  From then on, the
+// passing results are reported without executing the checker again.  A
+// checker that reports no results, such as a Registry with no matching
+// Checkers yet, isn't latched so that Checkers registered later are still
+// executed.
+func Latch(checker Checker) ContextChecker {
+	return &latch{checker: WithContext(checker)}
+}
+
+type latch struct {
+	checker ContextChecker
+
+	mu      sync.Mutex
+	passed  bool
+	details []ComponentDetail
+}
+
+func (l *latch) Check() ([]ComponentDetail, Status) {
+	return l.CheckContext(context.Background())
+}
+
+func (l *latch) CheckContext(ctx context.Context) ([]ComponentDetail, Status) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.passed {
+		return l.details, Pass
+	}
+
+	// The whole checker must pass, not just the results selected by a
+	// Filter, which is applied to the reported results by the caller
+	details, status := l.checker.CheckContext(withoutFilter(ctx))
+	if status == Pass && len(details) > 0 {
+		l.passed, l.details = true, details
+	}
+	return details, status
+}
+
+func (l *latch) aggregates() bool {
+	return aggregates(l.checker)
+}
@@ -0,0 +1,84 @@
+package health
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type toggleChecker struct {
+	status *Status
+	runs   *int32
+}
+
+func (t toggleChecker) Check() ([]ComponentDetail, Status) {
+	atomic.AddInt32(t.runs, 1)
+	return []ComponentDetail{{Key: Key{ComponentName: "migrations"}, Status: *t.status}}, *t.status
+}
+
+func serveCode(handler http.Handler) int {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	return rec.Code
+}
+
+func TestProbeHandlersSelectByTag(t *testing.T) {
+	var r Registry
+	require.NoError(t, r.Register(Registration{Name: "db", Checker: detailChecker("db", Fail), Tags: []string{ReadinessTag}}))
+	require.NoError(t, r.Register(Registration{Name: "deadlock", Checker: detailChecker("deadlock", Pass), Tags: []string{LivenessTag}}))
+
+	assert.Equal(t, http.StatusOK, serveCode(NewLivenessHandler(Service{}, &r)))
+	assert.Equal(t, http.StatusServiceUnavailable, serveCode(NewReadinessHandler(Service{}, &r)))
+	assert.Equal(t, http.StatusOK, serveCode(NewStartupHandler(Service{}, &r)))
+}
+
+func TestStartupHandlerLatches(t *testing.T) {
+	var r Registry
+	var runs int32
+	status := Fail
+	require.NoError(t, r.Register(Registration{
+		Name:    "migrations",
+		Checker: toggleChecker{status: &status, runs: &runs},
+		Tags:    []string{StartupTag},
+	}))
+	handler := NewStartupHandler(Service{}, &r)
+
+	assert.Equal(t, http.StatusServiceUnavailable, serveCode(handler))
+	status = Pass
+	assert.Equal(t, http.StatusOK, serveCode(handler))
+	status = Fail
+	assert.Equal(t, http.StatusOK, serveCode(handler))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&runs))
+}
+
+func TestStartupHandlerDoesNotLatchWithoutChecks(t *testing.T) {
+	var r Registry
+	var runs int32
+	status := Fail
+	handler := NewStartupHandler(Service{}, &r)
+
+	assert.Equal(t, http.StatusOK, serveCode(handler))
+	require.NoError(t, r.Register(Registration{
+		Name:    "migrations",
+		Checker: toggleChecker{status: &status, runs: &runs},
+		Tags:    []string{StartupTag},
+	}))
+	assert.Equal(t, http.StatusServiceUnavailable, serveCode(handler))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&runs))
+}
+
+func TestStartupHandlerDoesNotLatchFilteredResults(t *testing.T) {
+	var r Registry
+	require.NoError(t, r.Register(Registration{Name: "cache", Checker: detailChecker("cache", Pass), Tags: []string{StartupTag}}))
+	require.NoError(t, r.Register(Registration{Name: "migrations", Checker: detailChecker("migrations", Fail), Tags: []string{StartupTag}}))
+	handler := NewStartupHandler(Service{}, &r)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?component=cache", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, http.StatusServiceUnavailable, serveCode(handler))
+}
@@ -0,0 +1,75 @@
+package health
+
+// Redaction describes the parts of a Health document that are retained
+// when it is served to a caller that isn't authorized to see the details.
+// The zero value retains only the top-level Status.
+type Redaction struct {
+	// KeepService retains the service-level metadata (e.g. Version and
+	// ServiceId).
+	KeepService bool
+	// KeepKeys retains the Checks, with each ComponentDetail reduced to
+	// its Status and whichever of the fields below are kept.
+	KeepKeys                 bool
+	KeepComponent            bool // ComponentId and ComponentType
+	KeepObservedValue        bool // ObservedValue and ObservedUnit
+	KeepAffectedEndpoints    bool
+	KeepTime                 bool
+	KeepOutput               bool
+	KeepLinks                bool
+	KeepAdditionalProperties bool
+}
+
+// Apply returns a redacted copy of the Health document.
+func (r Redaction) Apply(h Health) Health {
+	redacted := Health{Status: h.Status}
+	if r.KeepService {
+		redacted.Version = h.Version
+		redacted.ReleaseId = h.ReleaseId
+		redacted.Notes = h.Notes
+		redacted.ServiceId = h.ServiceId
+		redacted.Description = h.Description
+		redacted.Links = h.Links
+	}
+	if !r.KeepKeys {
+		return redacted
+	}
+
+	redacted.Checks = Checks{}
+	for key, details := range h.Checks {
+		for _, d := range details {
+			redacted.Checks.Add(key, r.redactDetail(d))
+		}
+	}
+	return redacted
+}
+
+func (r Redaction) redactDetail(d ComponentDetail) ComponentDetail {
+	redacted := ComponentDetail{
+		Key:    d.Key,
+		Status: d.Status,
+	}
+	if r.KeepComponent {
+		redacted.ComponentId = d.ComponentId
+		redacted.ComponentType = d.ComponentType
+	}
+	if r.KeepObservedValue {
+		redacted.ObservedValue = d.ObservedValue
+		redacted.ObservedUnit = d.ObservedUnit
+	}
+	if r.KeepAffectedEndpoints {
+		redacted.AffectedEndpoints = d.AffectedEndpoints
+	}
+	if r.KeepTime {
+		redacted.Time = d.Time
+	}
+	if r.KeepOutput {
+		redacted.Output = d.Output
+	}
+	if r.KeepLinks {
+		redacted.Links = d.Links
+	}
+	if r.KeepAdditionalProperties {
+		redacted.AdditionalProperties = d.AdditionalProperties
+	}
+	return redacted
+}
@@ -0,0 +1,106 @@
+package health
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var sensitiveKey = Key{ComponentName: "db", MeasurementName: "connections"}
+
+func sensitiveHealth() Health {
+	return Health{
+		Status:    Warn,
+		Version:   "1",
+		ServiceId: "authz",
+		Links:     map[string]string{"about": "http://internal.example.com"},
+		Checks: Checks{
+			sensitiveKey: []ComponentDetail{{
+				Key:                  sensitiveKey,
+				ComponentId:          "db-1",
+				ComponentType:        "datastore",
+				ObservedValue:        75,
+				ObservedUnit:         "connections",
+				Status:               Warn,
+				AffectedEndpoints:    []string{"/users"},
+				Time:                 time.Now().UTC(),
+				Output:               "dial tcp 10.0.0.1:5432",
+				Links:                map[string]string{"self": "http://db.internal"},
+				AdditionalProperties: map[string]interface{}{"node": 1},
+			}},
+		},
+	}
+}
+
+func TestRedactionStatusOnly(t *testing.T) {
+	assert.Equal(t, Health{Status: Warn}, Redaction{}.Apply(sensitiveHealth()))
+}
+
+func TestRedactionStatusAndKeys(t *testing.T) {
+	assert := assert.New(t)
+	h := Redaction{KeepKeys: true}.Apply(sensitiveHealth())
+
+	assert.Empty(h.Version)
+	assert.Empty(h.Links)
+	require.Len(t, h.Checks[sensitiveKey], 1)
+	assert.Equal(ComponentDetail{Key: sensitiveKey, Status: Warn}, h.Checks[sensitiveKey][0])
+}
+
+func TestRedactionPerField(t *testing.T) {
+	assert := assert.New(t)
+	original := sensitiveHealth()
+	h := Redaction{KeepService: true, KeepKeys: true, KeepObservedValue: true, KeepTime: true}.Apply(original)
+
+	assert.Equal(original.Version, h.Version)
+	assert.Equal(original.Links, h.Links)
+	d := h.Checks[sensitiveKey][0]
+	expected := original.Checks[sensitiveKey][0]
+	assert.Equal(expected.ObservedValue, d.ObservedValue)
+	assert.Equal(expected.ObservedUnit, d.ObservedUnit)
+	assert.Equal(expected.Time, d.Time)
+	assert.Empty(d.ComponentId)
+	assert.Empty(d.Output)
+	assert.Empty(d.Links)
+	assert.Empty(d.AffectedEndpoints)
+	assert.Empty(d.AdditionalProperties)
+}
+
+func TestHandlerRedactsUnauthorizedRequests(t *testing.T) {
+	assert := assert.New(t)
+	handler := NewHandler(Service{Version: "1"}, testChecker{
+		details: sensitiveHealth().Checks[sensitiveKey],
+		status:  Warn,
+	})
+	handler.Authorizer = func(r *http.Request) bool {
+		return r.Header.Get("Authorization") == "secret"
+	}
+	handler.Redaction = Redaction{KeepKeys: true}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Contains(rec.Body.String(), `"db:connections"`)
+	assert.NotContains(rec.Body.String(), "10.0.0.1")
+	assert.NotContains(rec.Body.String(), `"version"`)
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	req.Header.Set("Authorization", "secret")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Contains(rec.Body.String(), "10.0.0.1")
+	assert.Contains(rec.Body.String(), `"version"`)
+}
+
+func TestHandlerIgnoresFiltersWhenKeysAreHidden(t *testing.T) {
+	handler := NewHandler(Service{}, detailChecker("db", Fail), detailChecker("cpu", Pass))
+	handler.Authorizer = func(*http.Request) bool { return false }
+
+	code, h := serveFiltered(t, handler, "component=cpu")
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, Health{Status: Fail}, h)
+}
@@ -0,0 +1,220 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Registration describes a named Checker held by a Registry.
+type Registration struct {
+	// Name uniquely identifies the Checker within the Registry and is used
+	// as the ComponentName of any ComponentDetail synthesized on its
+	// behalf.
+	Name    string
+	Checker Checker
+	// Tags assign the Checker to zero or more groups.
+	Tags []string
+	// Timeout bounds each execution of the Checker.  Executions are
+	// shared by concurrent evaluations, so one continues until it returns
+	// or Timeout elapses even after the evaluation that started it stops
+	// waiting (e.g. because the prober hung up).  A zero Timeout allows
+	// thirty seconds.
+	Timeout time.Duration
+	// WarnOnTimeout reports a timeout as Warn rather than Fail.
+	WarnOnTimeout bool
+	// DependsOn names the registered Checkers that must not be failing for
+	// this Checker to be executed.  Dependencies may be registered after
+	// their dependents.
+	DependsOn []string
+	// MinInterval is the minimum duration between executions of the
+	// Checker, within which its previous results are reused.  Concurrent
+	// executions of a registered Checker are always coalesced.
+	MinInterval time.Duration
+
+	coalescer *Coalescer
+}
+
+// HasTag returns true if the Registration carries any of the provided
+// tags.
+func (r Registration) HasTag(tags ...string) bool {
+	for _, want := range tags {
+		for _, tag := range r.Tags {
+			if tag == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// timeoutChecker reports the Registration under its Name.  The Timeout is
+// enforced by the coalescer.
+func (r Registration) timeoutChecker() TimeoutChecker {
+	return TimeoutChecker{
+		Key:     Key{ComponentName: r.Name},
+		Checker: r.coalescer,
+		Warn:    r.WarnOnTimeout,
+	}
+}
+
+// Registry is a thread-safe set of named Checkers that can be changed at
+// runtime.  The Registry is itself a Checker that concurrently executes
+// every registered Checker, so a Handler created with the Registry serves
+// the current set of checks without being rebuilt.
+//
+// Checkers are executed in dependency order.  A Checker whose dependency
+// reports Fail or Undetermined, or isn't registered, isn't executed and is
+// instead reported as Undetermined with an Output naming the blocking
+// dependency.  Concurrent executions of the same registered Checker, such
+// as those caused by simultaneous requests to a Handler, are coalesced
+// into one.
+//
+// The zero value is an empty Registry ready to use.
+type Registry struct {
+	// MaxConcurrency limits the number of registered Checkers that run
+	// at the same time.  A zero MaxConcurrency runs every Checker at once.
+	MaxConcurrency int
+
+	mu            sync.RWMutex
+	registrations []Registration
+}
+
+// Register adds the Registration to the Registry.  An error is returned
+// if the Registration has no Name or Checker, if its Name is already
+// registered or if its dependencies would form a cycle.
+func (r *Registry) Register(reg Registration) error {
+	if reg.Name == "" {
+		return fmt.Errorf("Registration is missing a name")
+	}
+	if reg.Checker == nil {
+		return fmt.Errorf("Registration is missing a checker: %v", reg.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.indexOf(reg.Name) >= 0 {
+		return fmt.Errorf("Check is already registered with name: %v", reg.Name)
+	}
+	reg.Tags = append([]string(nil), reg.Tags...)
+	reg.DependsOn = append([]string(nil), reg.DependsOn...)
+	if cycle := findCycle(append(r.registrations[:len(r.registrations):len(r.registrations)], reg), reg.Name); cycle != nil {
+		return fmt.Errorf("Check dependencies form a cycle: %v", strings.Join(cycle, " -> "))
+	}
+	reg.coalescer = &Coalescer{
+		Key:         Key{ComponentName: reg.Name},
+		Checker:     reg.Checker,
+		MinInterval: reg.MinInterval,
+		Timeout:     reg.Timeout,
+		Warn:        reg.WarnOnTimeout,
+	}
+	r.registrations = append(r.registrations, reg)
+	return nil
+}
+
+// Unregister removes the named Checker from the Registry and returns
+// true if it was present.
+func (r *Registry) Unregister(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.indexOf(name)
+	if i < 0 {
+		return false
+	}
+	registrations := make([]Registration, 0, len(r.registrations)-1)
+	registrations = append(registrations, r.registrations[:i]...)
+	r.registrations = append(registrations, r.registrations[i+1:]...)
+	return true
+}
+
+// Registrations lists the registered Checkers, in the order they were
+// registered.  If tags are provided, only the Registrations carrying at
+// least one of them are listed.
+func (r *Registry) Registrations(tags ...string) []Registration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var registrations []Registration
+	for _, reg := range r.registrations {
+		if len(tags) == 0 || reg.HasTag(tags...) {
+			registrations = append(registrations, reg)
+		}
+	}
+	return registrations
+}
+
+// Tagged returns a Checker that executes the Registrations carrying at
+// least one of the provided tags.  The selection is made each time the
+// returned Checker is executed, so it reflects later changes to the
+// Registry.
+func (r *Registry) Tagged(tags ...string) ContextChecker {
+	return registryView{registry: r, tags: tags}
+}
+
+// Check executes every registered Checker.
+func (r *Registry) Check() ([]ComponentDetail, Status) {
+	return r.CheckContext(context.Background())
+}
+
+// CheckContext concurrently executes every registered Checker.
+func (r *Registry) CheckContext(ctx context.Context) ([]ComponentDetail, Status) {
+	return r.run(ctx, r.Registrations())
+}
+
+// run executes the registrations, skipping those that can't be selected
+// by the Filter carried by ctx.  Any unselected dependencies are executed
+// but their results aren't reported.
+func (r *Registry) run(ctx context.Context, registrations []Registration) ([]ComponentDetail, Status) {
+	filter, filtered := filterFromContext(ctx)
+	var selected []Registration
+	for _, reg := range registrations {
+		if !filtered || filter.matchesName(reg.Name) {
+			selected = append(selected, reg)
+		}
+	}
+
+	results := r.runInOrder(ctx, r.withDependencies(selected))
+
+	var details []ComponentDetail
+	status := Pass
+	for _, reg := range selected {
+		res := results[reg.Name]
+		if filtered {
+			res.details, res.status = filter.apply(res.details, res.status)
+		}
+		details = append(details, res.details...)
+		status = status.Max(res.status)
+	}
+	return details, status
+}
+
+func (r *Registry) indexOf(name string) int {
+	for i, reg := range r.registrations {
+		if reg.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *Registry) aggregates() bool {
+	return true
+}
+
+type registryView struct {
+	registry *Registry
+	tags     []string
+}
+
+func (v registryView) Check() ([]ComponentDetail, Status) {
+	return v.CheckContext(context.Background())
+}
+
+func (v registryView) CheckContext(ctx context.Context) ([]ComponentDetail, Status) {
+	return v.registry.run(ctx, v.registry.Registrations(v.tags...))
+}
+
+func (v registryView) aggregates() bool {
+	return true
+}
@@ -0,0 +1,197 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func detailChecker(name string, status Status) Checker {
+	return testChecker{
+		details: []ComponentDetail{{Key: Key{ComponentName: name}, Status: status}},
+		status:  status,
+	}
+}
+
+func registrationNames(registrations []Registration) []string {
+	var names []string
+	for _, reg := range registrations {
+		names = append(names, reg.Name)
+	}
+	return names
+}
+
+func TestRegistryRegisterValidation(t *testing.T) {
+	var r Registry
+
+	assert.Error(t, r.Register(Registration{Checker: testChecker{}}))
+	assert.Error(t, r.Register(Registration{Name: "db"}))
+	assert.NoError(t, r.Register(Registration{Name: "db", Checker: testChecker{}}))
+	assert.Error(t, r.Register(Registration{Name: "db", Checker: testChecker{}}))
+}
+
+func TestRegistryUnregister(t *testing.T) {
+	var r Registry
+	require.NoError(t, r.Register(Registration{Name: "a", Checker: detailChecker("a", Pass)}))
+	require.NoError(t, r.Register(Registration{Name: "b", Checker: detailChecker("b", Fail)}))
+	require.NoError(t, r.Register(Registration{Name: "c", Checker: detailChecker("c", Pass)}))
+
+	assert.True(t, r.Unregister("b"))
+	assert.False(t, r.Unregister("b"))
+	assert.Equal(t, []string{"a", "c"}, registrationNames(r.Registrations()))
+
+	details, status := r.Check()
+	assert.Equal(t, Pass, status)
+	assert.Len(t, details, 2)
+}
+
+func TestRegistryTags(t *testing.T) {
+	assert := assert.New(t)
+	var r Registry
+	require.NoError(t, r.Register(Registration{Name: "db", Checker: detailChecker("db", Fail), Tags: []string{"readiness"}}))
+	require.NoError(t, r.Register(Registration{Name: "cpu", Checker: detailChecker("cpu", Warn), Tags: []string{"liveness", "system"}}))
+	require.NoError(t, r.Register(Registration{Name: "disk", Checker: detailChecker("disk", Pass), Tags: []string{"system"}}))
+
+	assert.Equal([]string{"cpu", "disk"}, registrationNames(r.Registrations("system")))
+	assert.Equal([]string{"db", "cpu"}, registrationNames(r.Registrations("readiness", "liveness")))
+	assert.Empty(r.Registrations("missing"))
+
+	liveness := r.Tagged("liveness")
+	details, status := liveness.Check()
+	assert.Equal(Warn, status)
+	require.Len(t, details, 1)
+	assert.Equal("cpu", details[0].Key.ComponentName)
+
+	require.NoError(t, r.Register(Registration{Name: "deadlock", Checker: detailChecker("deadlock", Fail), Tags: []string{"liveness"}}))
+	details, status = liveness.Check()
+	assert.Equal(Fail, status)
+	assert.Len(details, 2)
+}
+
+func TestRegistryTimeoutUsesName(t *testing.T) {
+	var r Registry
+	require.NoError(t, r.Register(Registration{
+		Name:          "slow",
+		Checker:       slowChecker{delay: time.Second},
+		Timeout:       10 * time.Millisecond,
+		WarnOnTimeout: true,
+	}))
+
+	details, status := r.CheckContext(context.Background())
+
+	assert.Equal(t, Warn, status)
+	require.Len(t, details, 1)
+	assert.Equal(t, Key{ComponentName: "slow"}, details[0].Key)
+}
+
+func TestRegistryChangesAfterHandlerIsBuilt(t *testing.T) {
+	var r Registry
+	handler := NewHandler(Service{}, &r)
+	serve := func() int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve())
+	require.NoError(t, r.Register(Registration{Name: "db", Checker: detailChecker("db", Fail)}))
+	assert.Equal(t, http.StatusServiceUnavailable, serve())
+	r.Unregister("db")
+	assert.Equal(t, http.StatusOK, serve())
+}
+
+func TestRegistryConcurrentUse(t *testing.T) {
+	var r Registry
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := string(rune('a' + i))
+			assert.NoError(t, r.Register(Registration{Name: name, Checker: detailChecker(name, Pass)}))
+			r.Check()
+			r.Unregister(name)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Empty(t, r.Registrations())
+}
+
+func TestRegistryDependencyCycle(t *testing.T) {
+	var r Registry
+	require.NoError(t, r.Register(Registration{Name: "a", Checker: testChecker{}, DependsOn: []string{"b"}}))
+	require.NoError(t, r.Register(Registration{Name: "b", Checker: testChecker{}, DependsOn: []string{"c"}}))
+
+	err := r.Register(Registration{Name: "c", Checker: testChecker{}, DependsOn: []string{"a"}})
+	require.Error(t, err)
+	assert.Equal(t, "Check dependencies form a cycle: c -> a -> b -> c", err.Error())
+	assert.Error(t, r.Register(Registration{Name: "d", Checker: testChecker{}, DependsOn: []string{"d"}}))
+	assert.Equal(t, []string{"a", "b"}, registrationNames(r.Registrations()))
+}
+
+func TestRegistrySkipsDependentsOfFailure(t *testing.T) {
+	assert := assert.New(t)
+	var runs int32
+	var r Registry
+	require.NoError(t, r.Register(Registration{Name: "query", Checker: runCounter{key: Key{ComponentName: "query"}, runs: &runs}, DependsOn: []string{"postgres"}}))
+	require.NoError(t, r.Register(Registration{Name: "report", Checker: runCounter{key: Key{ComponentName: "report"}, runs: &runs}, DependsOn: []string{"query"}}))
+	require.NoError(t, r.Register(Registration{Name: "postgres", Checker: detailChecker("postgres", Fail)}))
+	require.NoError(t, r.Register(Registration{Name: "cpu", Checker: detailChecker("cpu", Pass)}))
+
+	details, status := r.Check()
+	assert.Equal(Fail, status)
+	assert.Equal(int32(0), atomic.LoadInt32(&runs))
+	require.Len(t, details, 4)
+	assert.Equal(Key{ComponentName: "query"}, details[0].Key)
+	assert.Equal(Undetermined, details[0].Status)
+	assert.Equal(`Skipped because dependency "postgres" is failing`, details[0].Output)
+	assert.Equal(`Skipped because dependency "query" is undetermined`, details[1].Output)
+	assert.Equal(Fail, details[2].Status)
+	assert.Equal(Pass, details[3].Status)
+}
+
+func TestRegistryRunsDependentsOfPassingChecks(t *testing.T) {
+	var runs int32
+	var r Registry
+	require.NoError(t, r.Register(Registration{Name: "postgres", Checker: detailChecker("postgres", Warn)}))
+	require.NoError(t, r.Register(Registration{Name: "query", Checker: runCounter{key: Key{ComponentName: "query"}, runs: &runs}, DependsOn: []string{"postgres"}}))
+
+	details, status := r.Check()
+	assert.Equal(t, Warn, status)
+	assert.Len(t, details, 2)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&runs))
+}
+
+func TestRegistrySkipsUnregisteredDependency(t *testing.T) {
+	var r Registry
+	require.NoError(t, r.Register(Registration{Name: "query", Checker: detailChecker("query", Pass), DependsOn: []string{"postgres"}}))
+
+	details, status := r.Check()
+	assert.Equal(t, Undetermined, status)
+	require.Len(t, details, 1)
+	assert.Equal(t, `Skipped because dependency "postgres" isn't registered`, details[0].Output)
+}
+
+func TestRegistryRunsUnselectedDependencies(t *testing.T) {
+	var r Registry
+	require.NoError(t, r.Register(Registration{Name: "postgres", Checker: detailChecker("postgres", Fail), Tags: []string{"liveness"}}))
+	require.NoError(t, r.Register(Registration{Name: "query", Checker: detailChecker("query", Pass), Tags: []string{"readiness"}, DependsOn: []string{"postgres"}}))
+
+	details, status := r.Tagged("readiness").Check()
+	assert.Equal(t, Undetermined, status)
+	require.Len(t, details, 1)
+	assert.Equal(t, Key{ComponentName: "query"}, details[0].Key)
+
+	ctx := WithFilter(context.Background(), Filter{Components: []string{"query"}})
+	details, status = r.CheckContext(ctx)
+	assert.Equal(t, Undetermined, status)
+	require.Len(t, details, 1)
+}
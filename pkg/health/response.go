@@ -1,12 +1,19 @@
 package health
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 )
 
+// ContentType is the media type of the Health document as registered by
+// the RFC.
+const ContentType = "application/health+json"
+
 type Response struct {
 	Data []byte
 	Code int
@@ -17,35 +24,216 @@ type Checker interface {
 	Check() ([]ComponentDetail, Status)
 }
 
+// Service contains the service-level metadata that is copied into every
+// Health document served by a Handler.
+//
+// See - https://inadarei.github.io/rfc-healthcheck/#api-health-response
+type Service struct {
+	Version     string
+	ReleaseId   string
+	Notes       []string
+	ServiceId   string
+	Description string
+	Links       map[string]string
+}
+
+// Handler is an http.Handler that executes its Checkers on each request
+// and serves the results as a complete Health document.
+//
+// Each Checker is bounded by the request's context and, unless it is
+// already a TimeoutChecker, by the Handler's Timeout.
+type Handler struct {
+	Service  Service
+	Checkers []Checker
+	// Timeout is the default deadline for each Checker.  A zero Timeout
+	// bounds the Checkers only by the request's context.
+	Timeout time.Duration
+	// WarnOnTimeout reports Checkers that exceed their deadline as Warn
+	// rather than Fail.
+	WarnOnTimeout bool
+	// Deadline bounds the time taken to produce the whole Health document.
+	// Checkers that haven't finished when it elapses are reported as timed
+	// out.  A zero Deadline relies solely on the request's context.
+	Deadline time.Duration
+	// MaxConcurrency limits the number of Checkers that run at the same
+	// time.  A zero MaxConcurrency runs every Checker at once.
+	MaxConcurrency int
+	// StatusCodes overrides the HTTP response code used for each Status.
+	// Statuses that aren't present use the Status's default StatusCode.
+	StatusCodes map[Status]int
+	// Authorizer decides whether a request may see the detailed Health
+	// document.  Requests that aren't authorized are served the document
+	// redacted according to Redaction.  A nil Authorizer authorizes every
+	// request.
+	Authorizer func(r *http.Request) bool
+	Redaction  Redaction
+	// ValidateHealth validates each Health document against the RFC before
+	// it is served, logging any Violations and adding them to its Notes.
+	// This is intended as a debugging aid while developing Checkers.
+	ValidateHealth bool
+}
+
+// NewHandler returns a Handler that describes the provided Service and
+// executes the provided checkers.
+func NewHandler(service Service, checkers ...Checker) *Handler {
+	return &Handler{
+		Service:  service,
+		Checkers: checkers,
+	}
+}
+
+// GetHealthHandler returns an http.HandlerFunc that serves a Health
+// document without any service-level metadata.
 func GetHealthHandler(checkers ...Checker) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		checks := Checks{}
-		status := Pass
-		for _, checker := range checkers {
-			c, s := checker.Check()
-			checks.Add(c[0].Key, c...)
-			status = status.Max(s)
-		}
+	return NewHandler(Service{}, checkers...).ServeHTTP
+}
+
+// Health concurrently executes the Handler's Checkers and returns the
+// resulting Health document with its Status set to the most severe Status
+// reported.
+func (h *Handler) Health(ctx context.Context) Health {
+	if h.Deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.Deadline)
+		defer cancel()
+	}
+
+	checkers := make([]TimeoutChecker, len(h.Checkers))
+	for i, checker := range h.Checkers {
+		checkers[i] = h.timeoutChecker(checker)
+	}
+	details, status := runCheckers(ctx, h.MaxConcurrency, checkers...)
 
-		resp, err := json.Marshal(checks)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			errMsg := err.Error()
-			_, writeError := w.Write([]byte(errMsg))
-			if writeError != nil {
-				log.WithError(writeError).WithContext(r.Context()).WithField("Status", status).Errorf("Unable to write healthcheck error %v", err)
-			} else {
-				log.WithError(err).WithContext(r.Context()).WithField("Status", status).Error("Unable to marshal checks")
+	// A single invalid Key would prevent the whole document from being
+	// marshaled, so those details are omitted (but still contribute to the
+	// Status) and reported in the Notes instead
+	notes := h.Service.Notes
+	checks := Checks{}
+	for _, detail := range details {
+		if v := detail.Key.Validate(); len(v) != 0 {
+			if len(notes) == len(h.Service.Notes) {
+				notes = append([]string(nil), h.Service.Notes...)
 			}
-			return
+			notes = append(notes, fmt.Sprintf("Omitted %v check with an invalid key: %v", detail.Status, v))
+			log.WithContext(ctx).WithError(v).WithField("Status", detail.Status).Warn("Omitted check with an invalid key")
+			continue
 		}
+		checks.Add(detail.Key, detail)
+	}
+
+	return Health{
+		Status:      status,
+		Version:     h.Service.Version,
+		ReleaseId:   h.Service.ReleaseId,
+		Notes:       notes,
+		Checks:      checks,
+		Links:       h.Service.Links,
+		ServiceId:   h.Service.ServiceId,
+		Description: h.Service.Description,
+	}
+}
+
+// timeoutChecker applies the Handler's default Timeout to checkers that
+// don't provide their own.
+func (h *Handler) timeoutChecker(checker Checker) TimeoutChecker {
+	if tc, ok := checker.(TimeoutChecker); ok {
+		return tc
+	}
+	return TimeoutChecker{
+		Key:     defaultKey(checker),
+		Checker: checker,
+		Timeout: h.Timeout,
+		Warn:    h.WarnOnTimeout,
+	}
+}
 
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(status.StatusCode())
-		_, err = w.Write(resp)
-		if err != nil {
-			log.WithError(err).WithContext(r.Context()).WithField("Status", status).Error("Unable to write healthcheck response")
+// StatusCode returns the HTTP response code the Handler uses for the
+// provided Status.
+func (h *Handler) StatusCode(status Status) int {
+	if code, ok := h.StatusCodes[status]; ok {
+		return code
+	}
+	return status.StatusCode()
+}
+
+// validate logs the Health document's Violations and returns its Notes
+// with the Violations added.
+func (h *Handler) validate(r *http.Request, health Health) []string {
+	violations := health.Validate()
+	if len(violations) == 0 {
+		return health.Notes
+	}
+	log.WithError(violations).WithContext(r.Context()).Warn("Health document violates the RFC")
+	notes := append([]string(nil), health.Notes...)
+	for _, v := range violations {
+		notes = append(notes, "RFC violation: "+v.String())
+	}
+	return notes
+}
+
+// ServeHTTP responds to GET requests with the Health document and to HEAD
+// requests with only the response code.  Other methods are rejected.
+//
+// The Checks included in the Health document, and its aggregate Status,
+// can be restricted using the query parameters described by ParseFilter.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodHead)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	filter, err := ParseFilter(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, writeError := w.Write([]byte(err.Error()))
+		if writeError != nil {
+			log.WithError(writeError).WithContext(r.Context()).Error("Unable to write healthcheck filter error")
 		}
-		log.WithContext(r.Context()).WithField("Status", status).Debug("Checks: ", checks)
+		return
+	}
+
+	authorized := h.Authorizer == nil || h.Authorizer(r)
+	if !authorized && !h.Redaction.KeepKeys {
+		// Don't allow the hidden keys to be probed using the filter
+		filter = Filter{Terse: filter.Terse}
+	}
+
+	health := h.Health(WithFilter(r.Context(), filter))
+	status := health.Status
+	if h.ValidateHealth {
+		health.Notes = h.validate(r, health)
+	}
+	if filter.Terse {
+		health = Health{Status: status}
+	} else if !authorized {
+		health = h.Redaction.Apply(health)
+	}
+
+	if r.Method == http.MethodHead {
+		w.Header().Set("Content-Type", ContentType)
+		w.WriteHeader(h.StatusCode(status))
+		return
+	}
+
+	resp, err := json.Marshal(health)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		errMsg := err.Error()
+		_, writeError := w.Write([]byte(errMsg))
+		if writeError != nil {
+			log.WithError(writeError).WithContext(r.Context()).WithField("Status", status).Errorf("Unable to write healthcheck error %v", err)
+		} else {
+			log.WithError(err).WithContext(r.Context()).WithField("Status", status).Error("Unable to marshal health")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(h.StatusCode(status))
+	_, err = w.Write(resp)
+	if err != nil {
+		log.WithError(err).WithContext(r.Context()).WithField("Status", status).Error("Unable to write healthcheck response")
 	}
+	log.WithContext(r.Context()).WithField("Status", status).Debug("Checks: ", health.Checks)
 }
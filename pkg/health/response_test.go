@@ -0,0 +1,146 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testChecker struct {
+	details []ComponentDetail
+	status  Status
+}
+
+func (t testChecker) Check() ([]ComponentDetail, Status) {
+	return t.details, t.status
+}
+
+func TestHandlerServesHealthDocument(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	service := Service{
+		Version:     "1",
+		ReleaseId:   "1.2.2",
+		ServiceId:   "f03e522f-1f44-4062-9b55-9587f91c9c41",
+		Description: "health of authz service",
+		Links:       map[string]string{"about": "http://api.example.com/about/authz"},
+	}
+	key := Key{ComponentName: "cassandra", MeasurementName: "responseTime"}
+	checker := testChecker{
+		details: []ComponentDetail{{Key: key, Status: Warn}},
+		status:  Warn,
+	}
+
+	rec := httptest.NewRecorder()
+	NewHandler(service, checker).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal(ContentType, rec.Header().Get("Content-Type"))
+
+	var h Health
+	require.NoError(json.Unmarshal(rec.Body.Bytes(), &h))
+	assert.Equal(Warn, h.Status)
+	assert.Equal(service.Version, h.Version)
+	assert.Equal(service.ReleaseId, h.ReleaseId)
+	assert.Equal(service.ServiceId, h.ServiceId)
+	assert.Equal(service.Description, h.Description)
+	assert.Equal(service.Links, h.Links)
+	require.Len(h.Checks[key], 1)
+	assert.Equal(Warn, h.Checks[key][0].Status)
+}
+
+func TestHandlerAddsDetailsUnderTheirOwnKeys(t *testing.T) {
+	assert := assert.New(t)
+	status := Key{ComponentName: "db", MeasurementName: "status"}
+	latency := Key{ComponentName: "db", MeasurementName: "latency"}
+	checker := testChecker{
+		details: []ComponentDetail{{Key: status, Status: Fail}, {Key: latency}},
+		status:  Fail,
+	}
+
+	h := NewHandler(Service{}, checker).Health(context.Background())
+
+	assert.Equal(Fail, h.Status)
+	assert.Len(h.Checks[status], 1)
+	assert.Len(h.Checks[latency], 1)
+}
+
+func TestGetHealthHandlerReportsFailure(t *testing.T) {
+	checker := testChecker{
+		details: []ComponentDetail{{Key: Key{ComponentName: "db"}, Status: Fail}},
+		status:  Fail,
+	}
+
+	rec := httptest.NewRecorder()
+	GetHealthHandler(checker)(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"status":"fail"`)
+}
+
+func TestHandlerStatusCodes(t *testing.T) {
+	handler := NewHandler(Service{}, detailChecker("db", Warn))
+	handler.StatusCodes = map[Status]int{Warn: http.StatusTooManyRequests}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, http.StatusServiceUnavailable, handler.StatusCode(Fail))
+	assert.Equal(t, http.StatusOK, handler.StatusCode(Pass))
+}
+
+func TestHandlerStatusCodesAreIndependent(t *testing.T) {
+	multiStatus := NewHandler(Service{}, detailChecker("db", Warn))
+	multiStatus.StatusCodes = map[Status]int{Warn: http.StatusMultiStatus}
+	defaults := NewHandler(Service{}, detailChecker("db", Warn))
+
+	assert.Equal(t, http.StatusMultiStatus, serveCode(multiStatus))
+	assert.Equal(t, http.StatusOK, serveCode(defaults))
+}
+
+func TestHandlerHead(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewHandler(Service{}, detailChecker("db", Fail)).ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/health", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+	assert.Empty(t, rec.Body.Bytes())
+}
+
+func TestHandlerRejectsOtherMethods(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewHandler(Service{}, detailChecker("db", Pass)).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/health", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, HEAD", rec.Header().Get("Allow"))
+}
+
+func TestHandlerOmitsInvalidKeys(t *testing.T) {
+	handler := NewHandler(Service{Notes: []string{"canary"}},
+		detailChecker("db", Pass),
+		testChecker{
+			details: []ComponentDetail{{Key: Key{MeasurementName: "replicas"}, Status: Fail}},
+			status:  Fail,
+		},
+	)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code, rec.Body.String())
+	var h Health
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &h))
+	assert.Equal(t, Fail, h.Status)
+	assert.Contains(t, h.Checks, Key{ComponentName: "db"})
+	assert.Len(t, h.Checks, 1)
+	require.Len(t, h.Notes, 2)
+	assert.Contains(t, h.Notes[1], "Omitted Fail check with an invalid key")
+	assert.Equal(t, []string{"canary"}, handler.Service.Notes)
+}
@@ -0,0 +1,65 @@
+package health
+
+import (
+	"context"
+	"time"
+)
+
+type checkResult struct {
+	details []ComponentDetail
+	status  Status
+}
+
+// runCheckers executes the checkers concurrently, with no more than limit
+// running at once (or without a limit if limit is not positive), and
+// concatenates their results in the order the checkers were provided.
+// Checkers still waiting to run when ctx is done are reported as timed out
+// without being started.  If ctx carries a Filter, only the selected
+// results of each checker are included.
+func runCheckers(ctx context.Context, limit int, checkers ...TimeoutChecker) ([]ComponentDetail, Status) {
+	filter, filtered := filterFromContext(ctx)
+	var details []ComponentDetail
+	status := Pass
+	for _, r := range runEach(ctx, limit, checkers...) {
+		if filtered {
+			r.details, r.status = filter.apply(r.details, r.status)
+		}
+		details = append(details, r.details...)
+		status = status.Max(r.status)
+	}
+	return details, status
+}
+
+// runEach executes the checkers like runCheckers but returns the results
+// of each checker separately and unfiltered.
+func runEach(ctx context.Context, limit int, checkers ...TimeoutChecker) []checkResult {
+	results := make([]checkResult, len(checkers))
+	done := make(chan struct{}, len(checkers))
+
+	var slots chan struct{}
+	if limit > 0 {
+		slots = make(chan struct{}, limit)
+	}
+
+	start := time.Now().UTC()
+	for i := range checkers {
+		go func(i int) {
+			defer func() { done <- struct{}{} }()
+			tc := checkers[i]
+			if slots != nil {
+				select {
+				case slots <- struct{}{}:
+					defer func() { <-slots }()
+				case <-ctx.Done():
+					results[i].details, results[i].status = tc.timedOut(ctx.Err(), start)
+					return
+				}
+			}
+			results[i].details, results[i].status = tc.CheckContext(ctx)
+		}(i)
+	}
+	for range checkers {
+		<-done
+	}
+	return results
+}
@@ -0,0 +1,102 @@
+package health
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingChecker struct {
+	key     Key
+	delay   time.Duration
+	running *int32
+	peak    *int32
+}
+
+func (c countingChecker) Check() ([]ComponentDetail, Status) {
+	n := atomic.AddInt32(c.running, 1)
+	for {
+		p := atomic.LoadInt32(c.peak)
+		if n <= p || atomic.CompareAndSwapInt32(c.peak, p, n) {
+			break
+		}
+	}
+	time.Sleep(c.delay)
+	atomic.AddInt32(c.running, -1)
+	return []ComponentDetail{{Key: c.key}}, Pass
+}
+
+func countingCheckers(n int, delay time.Duration) ([]TimeoutChecker, *int32) {
+	var running, peak int32
+	var checkers []TimeoutChecker
+	for i := 0; i < n; i++ {
+		key := Key{ComponentName: string(rune('a' + i))}
+		checkers = append(checkers, TimeoutChecker{
+			Key:     key,
+			Checker: countingChecker{key: key, delay: delay, running: &running, peak: &peak},
+		})
+	}
+	return checkers, &peak
+}
+
+func TestRunCheckersConcurrently(t *testing.T) {
+	checkers, peak := countingCheckers(5, 50*time.Millisecond)
+
+	start := time.Now()
+	details, status := runCheckers(context.Background(), 0, checkers...)
+
+	assert.True(t, time.Since(start) < 200*time.Millisecond)
+	assert.Equal(t, Pass, status)
+	assert.Len(t, details, 5)
+	assert.Equal(t, int32(5), atomic.LoadInt32(peak))
+}
+
+func TestRunCheckersLimitsConcurrency(t *testing.T) {
+	checkers, peak := countingCheckers(6, 10*time.Millisecond)
+
+	details, status := runCheckers(context.Background(), 2, checkers...)
+
+	assert.Equal(t, Pass, status)
+	assert.Len(t, details, 6)
+	assert.Equal(t, int32(2), atomic.LoadInt32(peak))
+}
+
+func TestRunCheckersReturnsFinishedChecksAtDeadline(t *testing.T) {
+	assert := assert.New(t)
+	fast := Key{ComponentName: "fast"}
+	slow := Key{ComponentName: "slow"}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	details, status := runCheckers(ctx, 0,
+		TimeoutChecker{Key: fast, Checker: testChecker{details: []ComponentDetail{{Key: fast}}}},
+		TimeoutChecker{Key: slow, Checker: slowChecker{delay: time.Second}},
+	)
+
+	assert.Equal(Fail, status)
+	require.Len(t, details, 2)
+	assert.Equal(fast, details[0].Key)
+	assert.Equal(Pass, details[0].Status)
+	assert.Equal(slow, details[1].Key)
+	assert.Contains(details[1].Output, "timed out")
+}
+
+func TestRunCheckersTimesOutQueuedChecks(t *testing.T) {
+	checkers, _ := countingCheckers(3, time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	details, status := runCheckers(ctx, 1, checkers...)
+
+	assert.True(t, time.Since(start) < 500*time.Millisecond)
+	assert.Equal(t, Fail, status)
+	assert.Len(t, details, 3)
+	for _, detail := range details {
+		assert.Contains(t, detail.Output, "timed out")
+	}
+}
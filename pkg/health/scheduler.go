@@ -0,0 +1,218 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// minJitterDivisor limits Jitter so that an interval is never shortened to
+// less than this fraction of its length.
+const minJitterDivisor = 10
+
+// Scheduler executes Checkers in the background, each on its own interval,
+// and caches their most recent results.  The Scheduler is itself a Checker
+// that reports the cached results, so passing it to NewHandler serves the
+// latest snapshot without executing any checks per request.
+//
+// The zero value is a Scheduler without jitter that never reports results
+// as stale.
+type Scheduler struct {
+	// MaxAge is the age after which cached results are reported as stale.
+	// A zero MaxAge never considers results stale.
+	MaxAge time.Duration
+	// Jitter randomly adjusts each interval by up to the given fraction of
+	// its length (e.g. 0.1 is +/- 10%) so that checks don't run in lockstep.
+	// An interval is never shortened to less than a tenth of its length.
+	Jitter float64
+	// WarnWhenStale reports stale results as Warn rather than Fail.
+	WarnWhenStale bool
+
+	mu      sync.Mutex
+	entries []*scheduledCheck
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+type scheduledCheck struct {
+	checker  TimeoutChecker
+	interval time.Duration
+
+	mu      sync.RWMutex
+	details []ComponentDetail
+	status  Status
+	ran     time.Time
+}
+
+// Schedule adds a Checker that is executed every interval once the
+// Scheduler is started.  Unless the checker is a TimeoutChecker, each
+// execution is bounded by the interval.  An error is returned if the
+// interval isn't positive.
+func (s *Scheduler) Schedule(checker Checker, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("Interval must be positive: %v", interval)
+	}
+	tc, ok := checker.(TimeoutChecker)
+	if !ok {
+		tc = TimeoutChecker{
+			Key:     defaultKey(checker),
+			Checker: checker,
+			Timeout: interval,
+		}
+	}
+	sc := &scheduledCheck{
+		checker:  tc,
+		interval: interval,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, sc)
+	if s.ctx != nil {
+		s.wg.Add(1)
+		go s.run(s.ctx, sc)
+	}
+	return nil
+}
+
+// Start begins executing the scheduled Checkers.  Each Checker runs
+// immediately and then on its interval until ctx is done or Stop is called.
+// Calling Start on a running Scheduler has no effect.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx != nil {
+		return
+	}
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	for _, sc := range s.entries {
+		s.wg.Add(1)
+		go s.run(s.ctx, sc)
+	}
+}
+
+// Stop halts the scheduled Checkers and waits for any running checks to
+// return.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.ctx, s.cancel = nil, nil
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	s.wg.Wait()
+}
+
+// Check reports the most recent results of each scheduled Checker.
+func (s *Scheduler) Check() ([]ComponentDetail, Status) {
+	return s.CheckContext(context.Background())
+}
+
+// CheckContext reports the most recent results of each scheduled Checker
+// without executing them.  Results older than MaxAge are reported as
+// stale and Checkers that have not yet run are reported as Undetermined.
+func (s *Scheduler) CheckContext(ctx context.Context) ([]ComponentDetail, Status) {
+	s.mu.Lock()
+	entries := s.entries[:]
+	s.mu.Unlock()
+
+	var details []ComponentDetail
+	status := Pass
+	now := time.Now().UTC()
+	for _, sc := range entries {
+		d, st := s.snapshot(sc, now)
+		details = append(details, d...)
+		status = status.Max(st)
+	}
+	return details, status
+}
+
+func (s *Scheduler) snapshot(sc *scheduledCheck, now time.Time) ([]ComponentDetail, Status) {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	if sc.ran.IsZero() {
+		return []ComponentDetail{{
+			Key:    sc.checker.Key,
+			Status: Undetermined,
+			Output: "Check has not run yet",
+		}}, Undetermined
+	}
+
+	age := now.Sub(sc.ran)
+	if s.MaxAge <= 0 || age <= s.MaxAge {
+		return append([]ComponentDetail(nil), sc.details...), sc.status
+	}
+
+	staleStatus := Fail
+	if s.WarnWhenStale {
+		staleStatus = Warn
+	}
+
+	output := fmt.Sprintf("Stale result from %v ago", age.Round(time.Millisecond))
+	details := make([]ComponentDetail, len(sc.details))
+	for i, d := range sc.details {
+		d.Status = d.Status.Max(staleStatus)
+		if d.Output == "" {
+			d.Output = output
+		} else {
+			d.Output = output + ": " + d.Output
+		}
+		details[i] = d
+	}
+	return details, sc.status.Max(staleStatus)
+}
+
+func (s *Scheduler) aggregates() bool {
+	return true
+}
+
+func (s *Scheduler) run(ctx context.Context, sc *scheduledCheck) {
+	defer s.wg.Done()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		start := time.Now().UTC()
+		details, status := sc.checker.CheckContext(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		for i := range details {
+			if details[i].Time.IsZero() {
+				details[i].Time = start
+			}
+		}
+
+		sc.mu.Lock()
+		sc.details, sc.status, sc.ran = details, status, start
+		sc.mu.Unlock()
+
+		timer.Reset(s.jitter(sc.interval))
+	}
+}
+
+func (s *Scheduler) jitter(interval time.Duration) time.Duration {
+	if s.Jitter <= 0 {
+		return interval
+	}
+	delta := time.Duration((rand.Float64()*2 - 1) * s.Jitter * float64(interval))
+	if min := interval / minJitterDivisor; interval+delta < min {
+		return min
+	}
+	return interval + delta
+}
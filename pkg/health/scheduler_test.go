@@ -0,0 +1,119 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type runCounter struct {
+	key  Key
+	runs *int32
+}
+
+func (r runCounter) Check() ([]ComponentDetail, Status) {
+	atomic.AddInt32(r.runs, 1)
+	return []ComponentDetail{{Key: r.key}}, Pass
+}
+
+func TestSchedulerReportsNotYetRun(t *testing.T) {
+	var s Scheduler
+	require.NoError(t, s.Schedule(testChecker{}, time.Minute))
+
+	details, status := s.Check()
+
+	assert.Equal(t, Undetermined, status)
+	require.Len(t, details, 1)
+	assert.Equal(t, Key{ComponentName: "health.testChecker"}, details[0].Key)
+	assert.Equal(t, Undetermined, details[0].Status)
+	assert.Contains(t, details[0].Output, "not run")
+}
+
+func TestSchedulerServesCachedResults(t *testing.T) {
+	assert := assert.New(t)
+	var runs int32
+	key := Key{ComponentName: "db"}
+	s := &Scheduler{Jitter: 0.1}
+	require.NoError(t, s.Schedule(runCounter{key: key, runs: &runs}, 10*time.Millisecond))
+	s.Start(context.Background())
+	defer s.Stop()
+
+	time.Sleep(55 * time.Millisecond)
+	details, status := s.Check()
+
+	assert.Equal(Pass, status)
+	require.Len(t, details, 1)
+	assert.Equal(key, details[0].Key)
+	assert.False(details[0].Time.IsZero())
+	assert.True(atomic.LoadInt32(&runs) > 1)
+}
+
+func TestSchedulerHandlerDoesNotExecuteChecks(t *testing.T) {
+	var runs int32
+	s := &Scheduler{}
+	require.NoError(t, s.Schedule(runCounter{key: Key{ComponentName: "db"}, runs: &runs}, time.Minute))
+	s.Start(context.Background())
+	defer s.Stop()
+	time.Sleep(10 * time.Millisecond)
+
+	handler := NewHandler(Service{}, s)
+	for i := 0; i < 5; i++ {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&runs))
+}
+
+func TestSchedulerReportsStaleResults(t *testing.T) {
+	assert := assert.New(t)
+	var runs int32
+	s := &Scheduler{MaxAge: 10 * time.Millisecond, WarnWhenStale: true}
+	require.NoError(t, s.Schedule(runCounter{key: Key{ComponentName: "db"}, runs: &runs}, time.Minute))
+	s.Start(context.Background())
+	defer s.Stop()
+
+	time.Sleep(30 * time.Millisecond)
+	details, status := s.Check()
+
+	assert.Equal(Warn, status)
+	require.Len(t, details, 1)
+	assert.Equal(Warn, details[0].Status)
+	assert.Contains(details[0].Output, "Stale")
+}
+
+func TestSchedulerStop(t *testing.T) {
+	var runs int32
+	s := &Scheduler{}
+	require.NoError(t, s.Schedule(runCounter{key: Key{ComponentName: "db"}, runs: &runs}, 5*time.Millisecond))
+	s.Start(context.Background())
+	time.Sleep(20 * time.Millisecond)
+	s.Stop()
+	time.Sleep(5 * time.Millisecond)
+
+	stopped := atomic.LoadInt32(&runs)
+	time.Sleep(20 * time.Millisecond)
+
+	assert.Equal(t, stopped, atomic.LoadInt32(&runs))
+}
+
+func TestSchedulerRejectsNonPositiveInterval(t *testing.T) {
+	var s Scheduler
+	assert.Error(t, s.Schedule(testChecker{}, 0))
+	assert.Error(t, s.Schedule(testChecker{}, -time.Second))
+	assert.Empty(t, s.entries)
+}
+
+func TestSchedulerJitterKeepsIntervalPositive(t *testing.T) {
+	s := Scheduler{Jitter: 5}
+	for i := 0; i < 1000; i++ {
+		assert.True(t, s.jitter(time.Second) >= 100*time.Millisecond)
+	}
+}
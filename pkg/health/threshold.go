@@ -0,0 +1,121 @@
+package health
+
+import (
+	"sync"
+	"time"
+)
+
+// thresholdsProperty is the additional property that records the
+// Threshold applied to a ComponentDetail's ObservedValue.
+const thresholdsProperty = "thresholds"
+
+// Limit describes the bounds an observed value is expected to remain
+// within.  A Limit without either bound is never breached.
+type Limit struct {
+	Lower    float64
+	Upper    float64
+	HasLower bool
+	HasUpper bool
+}
+
+// UpperLimit returns a Limit that is breached by values above upper.
+func UpperLimit(upper float64) Limit {
+	return Limit{Upper: upper, HasUpper: true}
+}
+
+// LowerLimit returns a Limit that is breached by values below lower.
+func LowerLimit(lower float64) Limit {
+	return Limit{Lower: lower, HasLower: true}
+}
+
+// RangeLimit returns a Limit that is breached by values outside the range
+// from lower to upper.
+func RangeLimit(lower float64, upper float64) Limit {
+	return Limit{Lower: lower, Upper: upper, HasLower: true, HasUpper: true}
+}
+
+// Breached returns true if the value is outside the Limit.
+func (l Limit) Breached(value float64) bool {
+	return l.breachedWithin(value, 0)
+}
+
+// breachedWithin returns true if the value is outside the Limit once it
+// has been narrowed by margin.
+func (l Limit) breachedWithin(value float64, margin float64) bool {
+	return (l.HasUpper && value > l.Upper-margin) || (l.HasLower && value < l.Lower+margin)
+}
+
+func (l Limit) property() map[string]float64 {
+	p := map[string]float64{}
+	if l.HasLower {
+		p["lower"] = l.Lower
+	}
+	if l.HasUpper {
+		p["upper"] = l.Upper
+	}
+	return p
+}
+
+// Threshold derives a Status from an observed numeric value.  The value is
+// reported as Fail if it breaches the Fail Limit, as Warn if it breaches
+// the Warn Limit and as Pass otherwise.
+//
+// A non-zero Hysteresis keeps a breached Limit in effect until the value
+// has returned inside it by at least the Hysteresis, which prevents a
+// value hovering around a bound from flapping.  Since this requires the
+// previous Status to be retained, a Threshold must be used through a
+// pointer and should not be copied after first use.
+type Threshold struct {
+	Warn       Limit
+	Fail       Limit
+	Hysteresis float64
+
+	mu   sync.Mutex
+	last Status
+}
+
+// Evaluate returns the Status for the observed value.
+func (t *Threshold) Evaluate(value float64) Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	status := Pass
+	switch {
+	case t.breached(t.Fail, Fail, value):
+		status = Fail
+	case t.breached(t.Warn, Warn, value):
+		status = Warn
+	}
+	t.last = status
+	return status
+}
+
+func (t *Threshold) breached(l Limit, status Status, value float64) bool {
+	if l.Breached(value) {
+		return true
+	}
+	return t.last.Severity() >= status.Severity() && l.breachedWithin(value, t.Hysteresis)
+}
+
+// Detail evaluates the observed value and returns a ComponentDetail that
+// reports it, along with the Threshold applied in the "thresholds"
+// additional property.
+func (t *Threshold) Detail(key Key, value float64, unit string) ComponentDetail {
+	thresholds := map[string]interface{}{
+		"warn": t.Warn.property(),
+		"fail": t.Fail.property(),
+	}
+	if t.Hysteresis != 0 {
+		thresholds["hysteresis"] = t.Hysteresis
+	}
+	return ComponentDetail{
+		Key:           key,
+		ObservedValue: value,
+		ObservedUnit:  unit,
+		Status:        t.Evaluate(value),
+		Time:          time.Now().UTC(),
+		AdditionalProperties: map[string]interface{}{
+			thresholdsProperty: thresholds,
+		},
+	}
+}
@@ -0,0 +1,76 @@
+package health
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitBreached(t *testing.T) {
+	assert := assert.New(t)
+	assert.False(Limit{}.Breached(1e9))
+	assert.True(UpperLimit(80).Breached(81))
+	assert.False(UpperLimit(80).Breached(80))
+	assert.True(LowerLimit(10).Breached(9))
+	assert.False(LowerLimit(10).Breached(10))
+	assert.True(RangeLimit(10, 20).Breached(21))
+	assert.True(RangeLimit(10, 20).Breached(9))
+	assert.False(RangeLimit(10, 20).Breached(15))
+}
+
+func TestThresholdEvaluate(t *testing.T) {
+	assert := assert.New(t)
+	th := &Threshold{Warn: UpperLimit(70), Fail: UpperLimit(90)}
+
+	assert.Equal(Pass, th.Evaluate(50))
+	assert.Equal(Warn, th.Evaluate(75))
+	assert.Equal(Fail, th.Evaluate(95))
+	assert.Equal(Pass, th.Evaluate(69))
+}
+
+func TestThresholdLowerBounds(t *testing.T) {
+	th := &Threshold{Warn: LowerLimit(20), Fail: LowerLimit(5)}
+
+	assert.Equal(t, Pass, th.Evaluate(50))
+	assert.Equal(t, Warn, th.Evaluate(10))
+	assert.Equal(t, Fail, th.Evaluate(1))
+}
+
+func TestThresholdHysteresis(t *testing.T) {
+	assert := assert.New(t)
+	th := &Threshold{Warn: UpperLimit(70), Fail: UpperLimit(90), Hysteresis: 5}
+
+	assert.Equal(Fail, th.Evaluate(91))
+	assert.Equal(Fail, th.Evaluate(88))
+	assert.Equal(Warn, th.Evaluate(84))
+	assert.Equal(Warn, th.Evaluate(68))
+	assert.Equal(Pass, th.Evaluate(64))
+	assert.Equal(Pass, th.Evaluate(69))
+	assert.Equal(Warn, th.Evaluate(71))
+}
+
+func TestThresholdDetail(t *testing.T) {
+	assert := assert.New(t)
+	key := Key{ComponentName: "cpu", MeasurementName: "utilization"}
+	th := &Threshold{Warn: UpperLimit(70), Fail: RangeLimit(0, 90), Hysteresis: 2}
+
+	d := th.Detail(key, 85, "percent")
+
+	assert.Equal(key, d.Key)
+	assert.Equal(85.0, d.ObservedValue)
+	assert.Equal("percent", d.ObservedUnit)
+	assert.Equal(Warn, d.Status)
+	assert.False(d.Time.IsZero())
+
+	data, err := json.Marshal(d)
+	require.NoError(t, err)
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(map[string]interface{}{
+		"warn":       map[string]interface{}{"upper": 70.0},
+		"fail":       map[string]interface{}{"lower": 0.0, "upper": 90.0},
+		"hysteresis": 2.0,
+	}, decoded[thresholdsProperty])
+}
@@ -0,0 +1,92 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TimeoutChecker bounds the execution of its Checker.  If the Checker
+// doesn't return before the Timeout elapses, or before the context passed
+// to CheckContext is done, its results are abandoned and a single
+// ComponentDetail describing the timeout is reported under Key instead.
+// Likewise, a Checker that panics or returns no results is reported as a
+// single Fail ComponentDetail under Key.
+type TimeoutChecker struct {
+	// Key identifies the ComponentDetail synthesized on the Checker's
+	// behalf.  If it's empty, a Key derived from the Checker's type is used.
+	Key     Key
+	Checker Checker
+	// Timeout is the maximum duration the Checker is allowed to run.  A
+	// zero Timeout relies solely on the deadline of the context.
+	Timeout time.Duration
+	// Warn reports a timeout as Warn rather than Fail.
+	Warn bool
+}
+
+// Check executes the Checker with a background context.
+func (t TimeoutChecker) Check() ([]ComponentDetail, Status) {
+	return t.CheckContext(context.Background())
+}
+
+// CheckContext executes the Checker and waits for it to return, for the
+// Timeout to elapse or for ctx to be done - whichever happens first.
+func (t TimeoutChecker) CheckContext(ctx context.Context) ([]ComponentDetail, Status) {
+	if t.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.Timeout)
+		defer cancel()
+	}
+
+	type result struct {
+		details []ComponentDetail
+		status  Status
+	}
+
+	start := time.Now().UTC()
+	checker := WithContext(t.Checker)
+	results := make(chan result, 1)
+	go func() {
+		details, status := isolatedCheck(ctx, t.key(), checker)
+		results <- result{details, status}
+	}()
+
+	select {
+	case r := <-results:
+		return r.details, r.status
+	case <-ctx.Done():
+		return t.timedOut(ctx.Err(), start)
+	}
+}
+
+func (t TimeoutChecker) aggregates() bool {
+	return aggregates(t.Checker)
+}
+
+// timedOut synthesizes the results reported when the Checker is abandoned.
+func (t TimeoutChecker) timedOut(err error, start time.Time) ([]ComponentDetail, Status) {
+	status := Fail
+	if t.Warn {
+		status = Warn
+	}
+	return []ComponentDetail{{
+		Key:    t.key(),
+		Status: status,
+		Time:   start,
+		Output: timeoutOutput(err, time.Now().UTC().Sub(start)),
+	}}, status
+}
+
+func (t TimeoutChecker) key() Key {
+	if t.Key == (Key{}) {
+		return defaultKey(t.Checker)
+	}
+	return t.Key
+}
+
+func timeoutOutput(err error, elapsed time.Duration) string {
+	if err == context.DeadlineExceeded {
+		return fmt.Sprintf("Check timed out after %v", elapsed.Round(time.Millisecond))
+	}
+	return fmt.Sprintf("Check abandoned after %v: %v", elapsed.Round(time.Millisecond), err)
+}
@@ -0,0 +1,102 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type slowChecker struct {
+	delay time.Duration
+}
+
+func (s slowChecker) Check() ([]ComponentDetail, Status) {
+	time.Sleep(s.delay)
+	return []ComponentDetail{{Key: Key{ComponentName: "slow"}}}, Pass
+}
+
+func TestTimeoutCheckerReturnsResults(t *testing.T) {
+	tc := TimeoutChecker{
+		Key:     Key{ComponentName: "slow"},
+		Checker: slowChecker{},
+		Timeout: time.Second,
+	}
+
+	details, status := tc.Check()
+
+	assert.Equal(t, Pass, status)
+	require.Len(t, details, 1)
+	assert.Empty(t, details[0].Output)
+}
+
+func TestTimeoutCheckerSynthesizesFailure(t *testing.T) {
+	assert := assert.New(t)
+	key := Key{ComponentName: "slow", MeasurementName: "responseTime"}
+	tc := TimeoutChecker{
+		Key:     key,
+		Checker: slowChecker{delay: time.Second},
+		Timeout: 10 * time.Millisecond,
+	}
+
+	details, status := tc.Check()
+
+	assert.Equal(Fail, status)
+	require.Len(t, details, 1)
+	assert.Equal(key, details[0].Key)
+	assert.Equal(Fail, details[0].Status)
+	assert.Contains(details[0].Output, "timed out")
+}
+
+func TestTimeoutCheckerWarns(t *testing.T) {
+	tc := TimeoutChecker{
+		Checker: slowChecker{delay: time.Second},
+		Timeout: 10 * time.Millisecond,
+		Warn:    true,
+	}
+
+	details, status := tc.Check()
+
+	assert.Equal(t, Warn, status)
+	require.Len(t, details, 1)
+	assert.Equal(t, Warn, details[0].Status)
+}
+
+func TestTimeoutCheckerHonorsCancellation(t *testing.T) {
+	tc := TimeoutChecker{Checker: slowChecker{delay: time.Second}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	details, status := tc.CheckContext(ctx)
+
+	assert.Equal(t, Fail, status)
+	require.Len(t, details, 1)
+	assert.Contains(t, details[0].Output, context.Canceled.Error())
+}
+
+func TestHandlerAppliesDefaultTimeout(t *testing.T) {
+	assert := assert.New(t)
+	handler := NewHandler(Service{}, slowChecker{delay: time.Second})
+	handler.Timeout = 10 * time.Millisecond
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	assert.Equal(http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(rec.Body.String(), `"health.slowChecker"`)
+	assert.Contains(rec.Body.String(), "timed out")
+}
+
+func TestTimeoutCheckerWithoutKeyIsServed(t *testing.T) {
+	handler := NewHandler(Service{}, TimeoutChecker{Checker: slowChecker{delay: time.Second}, Timeout: 10 * time.Millisecond})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"health.slowChecker":[`)
+}
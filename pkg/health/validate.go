@@ -0,0 +1,120 @@
+package health
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Violation describes a way in which part of a Health document doesn't
+// conform to the RFC.
+type Violation struct {
+	// Path locates the offending value within the document (e.g.
+	// `checks["cpu:utilization"][0].status`).
+	Path    string
+	Message string
+}
+
+func (v Violation) String() string {
+	if v.Path == "" {
+		return v.Message
+	}
+	return v.Path + ": " + v.Message
+}
+
+// Violations lists the ways in which a Health document doesn't conform to
+// the RFC.  Validate methods return an empty list for valid values.
+type Violations []Violation
+
+func (v Violations) Error() string {
+	msgs := make([]string, len(v))
+	for i, violation := range v {
+		msgs[i] = violation.String()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// prefix returns the Violations with path prepended to each Path.
+func (v Violations) prefix(path string) Violations {
+	prefixed := make(Violations, len(v))
+	for i, violation := range v {
+		violation.Path = join(path, violation.Path)
+		prefixed[i] = violation
+	}
+	return prefixed
+}
+
+func join(path string, child string) string {
+	if path == "" {
+		return child
+	}
+	if child == "" || strings.HasPrefix(child, "[") {
+		return path + child
+	}
+	return path + "." + child
+}
+
+// Validate checks the Health document, and its Checks, against the RFC.
+func (h Health) Validate() Violations {
+	var v Violations
+	v = append(v, h.Status.validate().prefix("status")...)
+	v = append(v, h.Checks.Validate().prefix("checks")...)
+	return v
+}
+
+// Validate checks each Key and ComponentDetail against the RFC, along
+// with the agreement between each map key and the Key of its details.  The
+// Violations are ordered by key.
+func (c Checks) Validate() Violations {
+	keys := make([]Key, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	var v Violations
+	for _, key := range keys {
+		details := c[key]
+		path := fmt.Sprintf("[%q]", key.String())
+		v = append(v, key.Validate().prefix(path)...)
+		for i, detail := range details {
+			detailPath := fmt.Sprintf("%s[%d]", path, i)
+			if detail.Key != (Key{}) && detail.Key != key {
+				v = append(v, Violation{
+					Path:    detailPath,
+					Message: fmt.Sprintf("Key %q disagrees with the checks key", detail.Key.String()),
+				})
+			}
+			v = append(v, detail.Validate().prefix(detailPath)...)
+		}
+	}
+	return v
+}
+
+// Validate checks the Key against the RFC.
+func (k Key) Validate() Violations {
+	var v Violations
+	if k.ComponentName == "" {
+		v = append(v, Violation{Path: "componentName", Message: "ComponentName is required"})
+	}
+	return v
+}
+
+// Validate checks the ComponentDetail against the RFC.  The detail's Key
+// is only validated when it has been set, since it isn't part of the
+// detail's JSON representation.
+func (c ComponentDetail) Validate() Violations {
+	var v Violations
+	if c.Key != (Key{}) {
+		v = append(v, c.Key.Validate().prefix("key")...)
+	}
+	v = append(v, c.Status.validate().prefix("status")...)
+	return v
+}
+
+func (s Status) validate() Violations {
+	if s.valid() {
+		return nil
+	}
+	return Violations{{Message: fmt.Sprintf("%v is not a known status", s)}}
+}
@@ -0,0 +1,97 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateValidHealth(t *testing.T) {
+	key := Key{ComponentName: "cpu", MeasurementName: "utilization"}
+	h := Health{
+		Status: Warn,
+		Checks: Checks{key: []ComponentDetail{{Key: key, Status: Warn}, {Status: Pass}}},
+	}
+
+	assert.Empty(t, h.Validate())
+}
+
+func TestValidateKey(t *testing.T) {
+	v := Key{MeasurementName: "utilization"}.Validate()
+
+	require.Len(t, v, 1)
+	assert.Equal(t, "componentName", v[0].Path)
+}
+
+func TestValidateStatus(t *testing.T) {
+	v := Health{Status: Status(42)}.Validate()
+
+	require.Len(t, v, 1)
+	assert.Equal(t, "status: Status(42) is not a known status", v[0].String())
+}
+
+func TestValidateChecks(t *testing.T) {
+	assert := assert.New(t)
+	key := Key{ComponentName: "cpu", MeasurementName: "utilization"}
+	other := Key{ComponentName: "memory"}
+	c := Checks{
+		key:                              []ComponentDetail{{Key: other}, {Status: Status(-1)}},
+		Key{MeasurementName: "orphaned"}: []ComponentDetail{{}},
+	}
+
+	v := c.Validate()
+
+	require.Len(t, v, 3)
+	paths := map[string]bool{}
+	for _, violation := range v {
+		paths[violation.Path] = true
+	}
+	assert.True(paths[`["cpu:utilization"][0]`])
+	assert.True(paths[`["cpu:utilization"][1].status`])
+	assert.True(paths[`[":orphaned"].componentName`])
+	assert.Contains(v.Error(), "disagrees")
+}
+
+func TestHandlerValidatesInDebugMode(t *testing.T) {
+	key := Key{ComponentName: "cpu"}
+	handler := NewHandler(Service{Notes: []string{"original"}}, testChecker{
+		details: []ComponentDetail{{Key: key, Status: Status(7)}},
+	})
+	handler.ValidateHealth = true
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	notes := body["notes"].([]interface{})
+	require.Len(t, notes, 2)
+	assert.Equal(t, "original", notes[0])
+	assert.Contains(t, notes[1], `checks["cpu"][0].status`)
+	assert.Equal(t, []string{"original"}, handler.Service.Notes)
+}
+
+func TestValidateChecksOrdersViolations(t *testing.T) {
+	c := Checks{}
+	for _, name := range []string{"memory", "cassandra", "uptime", "cpu", "disk"} {
+		c[Key{ComponentName: name}] = []ComponentDetail{{Status: Status(-1)}}
+	}
+
+	for i := 0; i < 5; i++ {
+		var paths []string
+		for _, violation := range c.Validate() {
+			paths = append(paths, violation.Path)
+		}
+		assert.Equal(t, []string{
+			`["cassandra"][0].status`,
+			`["cpu"][0].status`,
+			`["disk"][0].status`,
+			`["memory"][0].status`,
+			`["uptime"][0].status`,
+		}, paths)
+	}
+}
@@ -0,0 +1,240 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/PennState/go-healthcheck/pkg/health"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultQueueSize = 100
+	defaultBackoff   = time.Second
+	defaultTimeout   = 10 * time.Second
+)
+
+// Payload is the JSON document POSTed to each webhook URL.
+type Payload struct {
+	Transitions []Transition `json:"transitions"`
+	// Dropped is the number of transitions that were discarded since the
+	// previous Payload because the queue was full.
+	Dropped int `json:"dropped,omitempty"`
+}
+
+// Transition is the JSON representation of a health.Transition.
+type Transition struct {
+	Key    string                  `json:"key,omitempty"`
+	From   health.Status           `json:"from"`
+	To     health.Status           `json:"to"`
+	Detail *health.ComponentDetail `json:"detail,omitempty"`
+	Time   time.Time               `json:"time"`
+}
+
+// Notifier POSTs health.Transitions to webhook URLs.  Its Notify method
+// can be passed directly to health.Observer's Subscribe method.
+//
+// Transitions are queued and delivered in the background once the
+// Notifier is started.  Transitions that arrive within Window of each
+// other are coalesced into a single Payload, failed deliveries are
+// retried with exponential backoff and, when the queue is full, the
+// oldest transitions are dropped so that an unresponsive receiver can't
+// exhaust memory.
+type Notifier struct {
+	URLs   []string
+	Client *http.Client
+	// Window is the time to wait for additional transitions before
+	// delivering a Payload.
+	Window time.Duration
+	// MaxRetries is the number of times a failed delivery is retried.
+	MaxRetries int
+	// Backoff is the delay before the first retry, which is doubled for
+	// each subsequent retry.  Defaults to one second.
+	Backoff time.Duration
+	// QueueSize is the maximum number of transitions waiting to be
+	// delivered.  Defaults to 100.
+	QueueSize int
+	// Timeout bounds each delivery attempt, so that a receiver that never
+	// responds can't stall delivery to the other URLs.  Defaults to ten
+	// seconds.
+	Timeout time.Duration
+
+	mu      sync.Mutex
+	queue   []Transition
+	dropped int
+	pending chan struct{}
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// Notify queues the transition for delivery without blocking.
+// Transitions that receivers can't distinguish, such as from Undetermined
+// to Fail (which are both encoded as "fail"), are discarded.
+func (n *Notifier) Notify(t health.Transition) {
+	if sameEncoding(t.From, t.To) {
+		return
+	}
+	transition := Transition{
+		From: t.From,
+		To:   t.To,
+		Time: t.Time,
+	}
+	if !t.Aggregate() {
+		transition.Key = t.Key.String()
+		detail := t.Detail
+		transition.Detail = &detail
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.init()
+
+	size := n.QueueSize
+	if size <= 0 {
+		size = defaultQueueSize
+	}
+	if len(n.queue) >= size {
+		n.queue = n.queue[1:]
+		n.dropped++
+	}
+	n.queue = append(n.queue, transition)
+
+	select {
+	case n.pending <- struct{}{}:
+	default:
+	}
+}
+
+func sameEncoding(a, b health.Status) bool {
+	textA, errA := a.MarshalText()
+	textB, errB := b.MarshalText()
+	return errA == nil && errB == nil && bytes.Equal(textA, textB)
+}
+
+// Start begins delivering queued transitions until ctx is done or Stop is
+// called.  Calling Start on a running Notifier has no effect.
+func (n *Notifier) Start(ctx context.Context) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.cancel != nil {
+		return
+	}
+	n.init()
+	ctx, n.cancel = context.WithCancel(ctx)
+	n.done = make(chan struct{})
+	go n.run(ctx, n.done)
+}
+
+// Stop halts delivery and waits for any in-progress delivery to be
+// abandoned.  Transitions still waiting in the queue remain queued.
+func (n *Notifier) Stop() {
+	n.mu.Lock()
+	cancel, done := n.cancel, n.done
+	n.cancel, n.done = nil, nil
+	n.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+func (n *Notifier) init() {
+	if n.pending == nil {
+		n.pending = make(chan struct{}, 1)
+	}
+}
+
+func (n *Notifier) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-n.pending:
+		}
+
+		if n.Window > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(n.Window):
+			}
+		}
+
+		n.mu.Lock()
+		payload := Payload{Transitions: n.queue, Dropped: n.dropped}
+		n.queue, n.dropped = nil, 0
+		n.mu.Unlock()
+
+		if len(payload.Transitions) == 0 {
+			continue
+		}
+		for _, url := range n.URLs {
+			err := n.deliver(ctx, url, payload)
+			if err != nil {
+				log.WithError(err).WithField("URL", url).Error("Unable to deliver health transitions")
+			}
+		}
+	}
+}
+
+// deliver POSTs the payload to url, retrying with exponential backoff.
+func (n *Notifier) deliver(ctx context.Context, url string, payload Payload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	backoff := n.Backoff
+	if backoff <= 0 {
+		backoff = defaultBackoff
+	}
+	for attempt := 0; ; attempt++ {
+		err = n.post(ctx, url, body)
+		if err == nil || attempt >= n.MaxRetries {
+			return err
+		}
+		log.WithError(err).WithField("URL", url).Debug("Retrying health transition delivery in ", backoff)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (n *Notifier) post(ctx context.Context, url string, body []byte) error {
+	timeout := n.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("Webhook responded with status: %v", resp.Status)
+	}
+	return nil
+}
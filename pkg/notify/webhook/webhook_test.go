@@ -0,0 +1,221 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PennState/go-healthcheck/pkg/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type receiver struct {
+	mu       sync.Mutex
+	payloads []Payload
+	attempts int
+	failures int
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempts++
+	if r.attempts <= r.failures {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	var p Payload
+	if err := json.NewDecoder(req.Body).Decode(&p); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.payloads = append(r.payloads, p)
+}
+
+func (r *receiver) received() ([]Payload, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Payload(nil), r.payloads...), r.attempts
+}
+
+func transition(name string, from, to health.Status) health.Transition {
+	key := health.Key{ComponentName: name}
+	return health.Transition{
+		Key:    key,
+		From:   from,
+		To:     to,
+		Detail: health.ComponentDetail{Key: key, Status: to},
+		Time:   time.Now().UTC(),
+	}
+}
+
+func waitFor(t *testing.T, r *receiver, n int) []Payload {
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		payloads, _ := r.received()
+		if len(payloads) >= n {
+			return payloads
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for %d payloads", n)
+	return nil
+}
+
+func TestNotifierCoalescesTransitions(t *testing.T) {
+	assert := assert.New(t)
+	r := &receiver{}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	n := &Notifier{URLs: []string{server.URL}, Window: 50 * time.Millisecond}
+	n.Start(context.Background())
+	defer n.Stop()
+
+	n.Notify(transition("db", health.Pass, health.Fail))
+	n.Notify(transition("cache", health.Pass, health.Warn))
+	n.Notify(health.Transition{From: health.Pass, To: health.Fail, Time: time.Now()})
+
+	payloads := waitFor(t, r, 1)
+	require.Len(t, payloads, 1)
+	require.Len(t, payloads[0].Transitions, 3)
+	assert.Equal("db", payloads[0].Transitions[0].Key)
+	assert.Equal(health.Pass, payloads[0].Transitions[0].From)
+	assert.Equal(health.Fail, payloads[0].Transitions[0].To)
+	require.NotNil(t, payloads[0].Transitions[0].Detail)
+	assert.Equal(health.Fail, payloads[0].Transitions[0].Detail.Status)
+	assert.Empty(payloads[0].Transitions[2].Key)
+	assert.Nil(payloads[0].Transitions[2].Detail)
+}
+
+func TestNotifierDiscardsIndistinguishableTransitions(t *testing.T) {
+	assert := assert.New(t)
+	r := &receiver{}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	n := &Notifier{URLs: []string{server.URL}, Window: 50 * time.Millisecond}
+	n.Start(context.Background())
+	defer n.Stop()
+
+	n.Notify(transition("query", health.Undetermined, health.Fail))
+	n.Notify(health.Transition{From: health.Fail, To: health.Undetermined, Time: time.Now()})
+	n.Notify(transition("db", health.Undetermined, health.Pass))
+
+	payloads := waitFor(t, r, 1)
+	require.Len(t, payloads, 1)
+	require.Len(t, payloads[0].Transitions, 1)
+	assert.Equal("db", payloads[0].Transitions[0].Key)
+	assert.Equal(health.Fail, payloads[0].Transitions[0].From)
+	assert.Equal(health.Pass, payloads[0].Transitions[0].To)
+}
+
+func TestNotifierRetriesWithBackoff(t *testing.T) {
+	r := &receiver{failures: 2}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	n := &Notifier{URLs: []string{server.URL}, MaxRetries: 3, Backoff: time.Millisecond}
+	n.Start(context.Background())
+	defer n.Stop()
+	n.Notify(transition("db", health.Pass, health.Fail))
+
+	payloads := waitFor(t, r, 1)
+	_, attempts := r.received()
+
+	assert.Len(t, payloads, 1)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestNotifierGivesUpAfterMaxRetries(t *testing.T) {
+	r := &receiver{failures: 10}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	n := &Notifier{URLs: []string{server.URL}, MaxRetries: 1, Backoff: time.Millisecond}
+	n.Start(context.Background())
+	n.Notify(transition("db", health.Pass, health.Fail))
+	time.Sleep(50 * time.Millisecond)
+	n.Stop()
+
+	payloads, attempts := r.received()
+	assert.Empty(t, payloads)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestNotifierBoundsQueue(t *testing.T) {
+	assert := assert.New(t)
+	r := &receiver{}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	n := &Notifier{URLs: []string{server.URL}, QueueSize: 2}
+	n.Notify(transition("a", health.Pass, health.Fail))
+	n.Notify(transition("b", health.Pass, health.Fail))
+	n.Notify(transition("c", health.Pass, health.Fail))
+	n.Start(context.Background())
+	defer n.Stop()
+
+	payloads := waitFor(t, r, 1)
+	require.Len(t, payloads[0].Transitions, 2)
+	assert.Equal("b", payloads[0].Transitions[0].Key)
+	assert.Equal("c", payloads[0].Transitions[1].Key)
+	assert.Equal(1, payloads[0].Dropped)
+}
+
+type flipChecker struct {
+	status *health.Status
+}
+
+func (f flipChecker) Check() ([]health.ComponentDetail, health.Status) {
+	return []health.ComponentDetail{{Key: health.Key{ComponentName: "db"}, Status: *f.status}}, *f.status
+}
+
+func TestNotifierSubscribedToObserver(t *testing.T) {
+	r := &receiver{}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	n := &Notifier{URLs: []string{server.URL}}
+	n.Start(context.Background())
+	defer n.Stop()
+
+	status := health.Fail
+	o := &health.Observer{Checker: flipChecker{status: &status}}
+	o.Subscribe(n.Notify)
+	o.Check()
+
+	payloads := waitFor(t, r, 1)
+	var keys []string
+	for _, p := range payloads {
+		for _, t := range p.Transitions {
+			keys = append(keys, t.Key)
+		}
+	}
+	assert.Contains(t, keys, "db")
+}
+
+func TestNotifierTimesOutUnresponsiveReceiver(t *testing.T) {
+	release := make(chan struct{})
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer hung.Close()
+	defer close(release)
+	r := &receiver{}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	n := &Notifier{URLs: []string{hung.URL, server.URL}, Timeout: 20 * time.Millisecond}
+	n.Start(context.Background())
+	defer n.Stop()
+
+	n.Notify(transition("db", health.Pass, health.Fail))
+	payloads := waitFor(t, r, 1)
+	assert.Len(t, payloads[0].Transitions, 1)
+}
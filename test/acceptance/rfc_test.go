@@ -3,6 +3,8 @@ package acceptance
 import (
 	"encoding/json"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
@@ -155,3 +157,47 @@ func TestRFCExampleCanBeUnmarshaled(t *testing.T) {
 	// TODO: Compare against "golden file" (or update)
 	// TODO: Round-trip the data and compare the source and result JSON
 }
+
+type rfcChecker struct {
+	key     health.Key
+	details []health.ComponentDetail
+}
+
+func (r rfcChecker) Check() ([]health.ComponentDetail, health.Status) {
+	var details []health.ComponentDetail
+	for _, d := range r.details {
+		d.Key = r.key
+		details = append(details, d)
+	}
+	return details, health.Pass
+}
+
+func TestRFCExampleCanBeServed(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	example := RfcExample()
+	var checkers []health.Checker
+	for k, v := range example.Checks {
+		checkers = append(checkers, rfcChecker{key: k, details: v})
+	}
+	handler := health.NewHandler(health.Service{
+		Version:     example.Version,
+		ReleaseId:   example.ReleaseId,
+		Notes:       example.Notes,
+		ServiceId:   example.ServiceId,
+		Description: example.Description,
+		Links:       example.Links,
+	}, checkers...)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal(health.ContentType, rec.Header().Get("Content-Type"))
+
+	var health health.Health
+	err := json.Unmarshal(rec.Body.Bytes(), &health)
+	require.NoError(err)
+
+	assert.Equal(example, health)
+}